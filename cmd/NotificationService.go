@@ -1,11 +1,183 @@
 package cmd
 
-import "sync"
+import (
+	"fmt"
+)
 
 type NotificationService interface {
-	// send one time alert for validator
-	SendValidatorAlertNotification(config *HalfLifeConfig, vm *ValidatorMonitor, stats ValidatorStats, alertNotification *ValidatorAlertNotification)
+	// send one time alert for validator, returns an error if any of it could not be sent
+	SendValidatorAlertNotification(config *HalfLifeConfig, vm *ValidatorMonitor, stats ValidatorStats, alertNotification *ValidatorAlertNotification) error
+
+	// send one time alert that is not specific to a validator, e.g. for config.yaml reload errors
+	SendAlertNotification(title string, alertNotification *ValidatorAlertNotification) error
 
 	// update (or create) realtime status for validator
-	UpdateValidatorRealtimeStatus(configFile string, config *HalfLifeConfig, vm *ValidatorMonitor, stats ValidatorStats, writeConfigMutex *sync.Mutex)
+	UpdateValidatorRealtimeStatus(config *HalfLifeConfig, vm *ValidatorMonitor, stats ValidatorStats, stateStore *StateStore)
+}
+
+func newNotificationService(sink *NotificationsConfig) (NotificationService, error) {
+	// TODO implement more notification services e.g. email
+	switch sink.Service {
+	case "discord":
+		if sink.Discord == nil {
+			return nil, fmt.Errorf("Discord configuration not present in config.yaml for notification %s", sink.Name)
+		}
+		return NewDiscordNotificationService(sink.Name, sink.Discord), nil
+	case "slack":
+		if sink.Slack == nil {
+			return nil, fmt.Errorf("Slack configuration not present in config.yaml for notification %s", sink.Name)
+		}
+		return NewSlackNotificationService(sink.Name, sink.Slack), nil
+	case "pagerduty":
+		if sink.PagerDuty == nil {
+			return nil, fmt.Errorf("PagerDuty configuration not present in config.yaml for notification %s", sink.Name)
+		}
+		return NewPagerDutyNotificationService(sink.PagerDuty), nil
+	default:
+		return nil, fmt.Errorf("Notification service not supported: %s", sink.Service)
+	}
+}
+
+type notificationSink struct {
+	config  *NotificationsConfig
+	service NotificationService
+}
+
+// MultiNotificationService fans out to every configured notification sink that matches the validator
+type MultiNotificationService struct {
+	sinks []notificationSink
+}
+
+func NewMultiNotificationService(sinks NotificationSinks) (*MultiNotificationService, error) {
+	service := &MultiNotificationService{}
+	for _, sink := range sinks {
+		sinkService, err := newNotificationService(sink)
+		if err != nil {
+			return nil, err
+		}
+		service.sinks = append(service.sinks, notificationSink{config: sink, service: sinkService})
+	}
+	return service, nil
+}
+
+// Alerts are only sent to sinks when they are at or above the sink's minimum alert level.
+// Cleared alerts are sent to the sinks that received the alerts they clear, or to every sink when that is unknown.
+func (n *ValidatorAlertNotification) forMinAlertLevel(minAlertLevel AlertLevel) *ValidatorAlertNotification {
+	sinkNotification := &ValidatorAlertNotification{NotifyForClear: n.NotifyForClear, AlertLevel: alertLevelNone}
+	for i, alert := range n.Alerts {
+		if n.AlertLevels[i] < minAlertLevel {
+			continue
+		}
+		sinkNotification.Alerts = append(sinkNotification.Alerts, alert)
+		sinkNotification.AlertKeys = append(sinkNotification.AlertKeys, n.AlertKeys[i])
+		sinkNotification.AlertLevels = append(sinkNotification.AlertLevels, n.AlertLevels[i])
+		if n.AlertLevels[i] > sinkNotification.AlertLevel {
+			sinkNotification.AlertLevel = n.AlertLevels[i]
+		}
+	}
+	for i, clearedAlert := range n.ClearedAlerts {
+		if n.ClearedAlertLevels[i] != alertLevelNone && n.ClearedAlertLevels[i] < minAlertLevel {
+			continue
+		}
+		sinkNotification.ClearedAlerts = append(sinkNotification.ClearedAlerts, clearedAlert)
+		sinkNotification.ClearedAlertKeys = append(sinkNotification.ClearedAlertKeys, n.ClearedAlertKeys[i])
+		sinkNotification.ClearedAlertLevels = append(sinkNotification.ClearedAlertLevels, n.ClearedAlertLevels[i])
+	}
+	if len(sinkNotification.Alerts) == 0 && len(sinkNotification.ClearedAlerts) == 0 {
+		return nil
+	}
+	return sinkNotification
+}
+
+// The alerts and cleared alerts of the notification, sent separately so the notifications sent of each kind
+// are only counted when they were sent successfully. Alerts are sent before cleared alerts.
+func (n *ValidatorAlertNotification) byKind() []kindNotification {
+	var notifications []kindNotification
+	if len(n.Alerts) > 0 {
+		notifications = append(notifications, kindNotification{"alert", &ValidatorAlertNotification{
+			Alerts:      n.Alerts,
+			AlertKeys:   n.AlertKeys,
+			AlertLevels: n.AlertLevels,
+			AlertLevel:  n.AlertLevel,
+		}})
+	}
+	if len(n.ClearedAlerts) > 0 {
+		notifications = append(notifications, kindNotification{"cleared", &ValidatorAlertNotification{
+			ClearedAlerts:      n.ClearedAlerts,
+			ClearedAlertKeys:   n.ClearedAlertKeys,
+			ClearedAlertLevels: n.ClearedAlertLevels,
+			NotifyForClear:     n.NotifyForClear,
+			AlertLevel:         n.AlertLevel,
+		}})
+	}
+	return notifications
+}
+
+type kindNotification struct {
+	kind         string
+	notification *ValidatorAlertNotification
+}
+
+// implements NotificationService interface
+func (service *MultiNotificationService) SendValidatorAlertNotification(
+	config *HalfLifeConfig,
+	vm *ValidatorMonitor,
+	stats ValidatorStats,
+	alertNotification *ValidatorAlertNotification,
+) error {
+	var sendErr error
+	for _, sink := range service.sinks {
+		if !sink.config.matches(vm) {
+			continue
+		}
+		sinkNotification := alertNotification.forMinAlertLevel(sink.config.MinAlertLevel)
+		if sinkNotification == nil {
+			continue
+		}
+		for _, kindNotification := range sinkNotification.byKind() {
+			err := sink.service.SendValidatorAlertNotification(config, vm, stats, kindNotification.notification)
+			if err != nil {
+				fmt.Printf("Error sending %s notification for %s: %v\n", sink.config.Name, vm.Name, err)
+				sendErr = err
+				continue
+			}
+			recordNotificationSent(vm.Name, sink.config.Name, kindNotification.kind)
+		}
+	}
+	return sendErr
+}
+
+// implements NotificationService interface
+func (service *MultiNotificationService) SendAlertNotification(title string, alertNotification *ValidatorAlertNotification) error {
+	var sendErr error
+	for _, sink := range service.sinks {
+		sinkNotification := alertNotification.forMinAlertLevel(sink.config.MinAlertLevel)
+		if sinkNotification == nil {
+			continue
+		}
+		for _, kindNotification := range sinkNotification.byKind() {
+			err := sink.service.SendAlertNotification(title, kindNotification.notification)
+			if err != nil {
+				fmt.Printf("Error sending %s notification: %v\n", sink.config.Name, err)
+				sendErr = err
+				continue
+			}
+			recordNotificationSent("", sink.config.Name, kindNotification.kind)
+		}
+	}
+	return sendErr
+}
+
+// implements NotificationService interface
+func (service *MultiNotificationService) UpdateValidatorRealtimeStatus(
+	config *HalfLifeConfig,
+	vm *ValidatorMonitor,
+	stats ValidatorStats,
+	stateStore *StateStore,
+) {
+	for _, sink := range service.sinks {
+		if sink.config.matches(vm) {
+			sink.service.UpdateValidatorRealtimeStatus(config, vm, stats, stateStore)
+		}
+	}
 }
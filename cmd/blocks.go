@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	blockStreamSubscriber    = "halflife"
+	blockStreamRetryInterval = 30 * time.Second
+	blockStreamStaleTimeout  = 2 * time.Minute // resubscribe if no blocks have been received for this long
+)
+
+// parts of a block that are used for monitoring, kept instead of the block so that transactions are not held in memory
+type blockRecord struct {
+	Height          int64
+	Time            time.Time
+	ProposerAddress types.Address
+	LastCommit      *types.Commit
+	Evidence        types.EvidenceList
+}
+
+func newBlockRecord(block *types.Block) *blockRecord {
+	return &blockRecord{
+		Height:          block.Height,
+		Time:            block.Time,
+		ProposerAddress: block.ProposerAddress,
+		LastCommit:      block.LastCommit,
+		Evidence:        block.Evidence.Evidence,
+	}
+}
+
+// blockWindow holds the most recent blocks of a validator's chain, so that blocks are only fetched over RPC once.
+// Blocks are added by the block stream as they are produced, and any gaps are backfilled from RPC when they are checked.
+type blockWindow struct {
+	mutex     sync.Mutex
+	blocks    map[int64]*blockRecord
+	maxHeight int64
+
+	// proposers of the rounds that were not committed, for heights that needed more than one round
+	roundProposers map[int64][]types.Address
+	keep           int64
+
+	// average block time over upgradeBlockTimeSampleBlocks, sampled at sampledBlockTimeHeight, for upgrade time estimates
+	sampledBlockTime       time.Duration
+	sampledBlockTimeHeight int64
+}
+
+func newBlockWindow() *blockWindow {
+	return &blockWindow{blocks: make(map[int64]*blockRecord), roundProposers: make(map[int64][]types.Address)}
+}
+
+// number of blocks, counting back from the latest block, to keep in the window
+func (w *blockWindow) setKeep(keep int64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.keep = keep
+	w.prune()
+}
+
+// removes all blocks, e.g. when the validator's chain is changed
+func (w *blockWindow) reset() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.blocks = make(map[int64]*blockRecord)
+	w.roundProposers = make(map[int64][]types.Address)
+	w.maxHeight = 0
+	w.sampledBlockTime = 0
+	w.sampledBlockTimeHeight = 0
+}
+
+// requires locked window
+func (w *blockWindow) prune() {
+	for height := range w.blocks {
+		if height <= w.maxHeight-w.keep {
+			delete(w.blocks, height)
+		}
+	}
+	for height := range w.roundProposers {
+		if height <= w.maxHeight-w.keep {
+			delete(w.roundProposers, height)
+		}
+	}
+}
+
+func (w *blockWindow) add(record *blockRecord) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if record.Height > w.maxHeight {
+		w.maxHeight = record.Height
+		w.prune()
+	}
+	if record.Height <= w.maxHeight-w.keep {
+		return
+	}
+	w.blocks[record.Height] = record
+}
+
+func (w *blockWindow) get(height int64) (*blockRecord, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	record, ok := w.blocks[height]
+	return record, ok
+}
+
+// block at height from the window, or fetched from RPC if it is not in the window
+func (w *blockWindow) block(node rpcclient.Client, height int64) (*blockRecord, error) {
+	if record, ok := w.get(height); ok {
+		return record, nil
+	}
+	blockCtx, blockCtxCancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+	block, err := node.Block(blockCtx, &height)
+	blockCtxCancel()
+	if err != nil {
+		return nil, err
+	}
+	record := newBlockRecord(block.Block)
+	w.add(record)
+	return record, nil
+}
+
+// blockStream subscribes to NewBlock events over the RPC server's websocket and adds the blocks to the block window
+type blockStream struct {
+	rpc  string
+	stop chan struct{}
+}
+
+func startBlockStream(rpc string, window *blockWindow) *blockStream {
+	stream := &blockStream{rpc: rpc, stop: make(chan struct{})}
+	go stream.run(window)
+	return stream
+}
+
+func (s *blockStream) close() {
+	close(s.stop)
+}
+
+func (s *blockStream) run(window *blockWindow) {
+	for {
+		err := s.subscribe(window)
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		fmt.Printf("Block subscription to %s dropped, polling for blocks until resubscribed: %v\n", s.rpc, err)
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(blockStreamRetryInterval):
+		}
+	}
+}
+
+// returns when the subscription fails or the stream is closed
+func (s *blockStream) subscribe(window *blockWindow) error {
+	client, err := newClient(Endpoint{URL: s.rpc})
+	if err != nil {
+		return err
+	}
+	if err := client.Start(); err != nil {
+		return err
+	}
+	defer func() {
+		_ = client.Stop()
+	}()
+
+	subscribeCtx, subscribeCtxCancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+	events, err := client.Subscribe(subscribeCtx, blockStreamSubscriber, types.EventQueryNewBlock.String(), 100)
+	subscribeCtxCancel()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Subscribed to new blocks from %s\n", s.rpc)
+
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		case event := <-events:
+			newBlock, ok := event.Data.(types.EventDataNewBlock)
+			if !ok || newBlock.Block == nil {
+				continue
+			}
+			window.add(newBlockRecord(newBlock.Block))
+		case <-time.After(blockStreamStaleTimeout):
+			return errors.New("no new blocks received")
+		}
+	}
+}
+
+// starts, restarts or stops the block stream to match the validator config and the rpc endpoint in use. Only called from the validator's monitor.
+func (runner *validatorRunner) updateBlockStream(vm *ValidatorMonitor, rpc Endpoint) {
+	stream := vm.StreamBlocks && !vm.FullNode
+	if runner.blockStream != nil && (!stream || runner.blockStream.rpc != rpc.URL) {
+		runner.blockStream.close()
+		runner.blockStream = nil
+	}
+	if runner.blockStream == nil && stream {
+		runner.blockStream = startBlockStream(rpc.URL, runner.blocks)
+	}
+}
+
+func (runner *validatorRunner) stopBlockStream() {
+	if runner.blockStream != nil {
+		runner.blockStream.close()
+		runner.blockStream = nil
+	}
+}
@@ -7,26 +7,36 @@ import (
 
 	cosmosClient "github.com/cosmos/cosmos-sdk/client"
 	tmservice "github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
 	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
 	libclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+	"github.com/tendermint/tendermint/types"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	sentryGRPCTimeoutSeconds = 5
 	RPCTimeoutSeconds        = 5
+	validatorsPerPage        = 100
 )
 
-func newClient(addr string) (rpcclient.Client, error) {
-	httpClient, err := libclient.DefaultHTTPClient(addr)
+func newClient(rpc Endpoint) (rpcclient.Client, error) {
+	httpClient, err := libclient.DefaultHTTPClient(rpc.URL)
 	if err != nil {
 		return nil, err
 	}
+	if err := rpc.configureHTTPClient(httpClient); err != nil {
+		return nil, err
+	}
 
 	httpClient.Timeout = 10 * time.Second
-	rpcClient, err := rpchttp.NewWithClient(addr, "/websocket", httpClient)
+	rpcClient, err := rpchttp.NewWithClient(rpc.URL, "/websocket", httpClient)
 	if err != nil {
 		return nil, err
 	}
@@ -34,8 +44,8 @@ func newClient(addr string) (rpcclient.Client, error) {
 	return rpcClient, nil
 }
 
-func getCosmosClient(rpcAddress string, chainID string) (*cosmosClient.Context, error) {
-	client, err := newClient(rpcAddress)
+func getCosmosClient(rpc Endpoint, chainID string) (*cosmosClient.Context, error) {
+	client, err := newClient(rpc)
 	if err != nil {
 		return nil, err
 	}
@@ -62,8 +72,79 @@ func getSigningInfo(client *cosmosClient.Context, address string) (*slashingtype
 	})
 }
 
-func getSentryInfo(grpcAddr string) (*tmservice.GetNodeInfoResponse, *tmservice.GetLatestBlockResponse, error) {
-	conn, err := grpc.Dial(grpcAddr, grpc.WithInsecure())
+func getUpgradePlan(client *cosmosClient.Context) (*upgradetypes.QueryCurrentPlanResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+	defer cancel()
+	return upgradetypes.NewQueryClient(client).CurrentPlan(ctx, &upgradetypes.QueryCurrentPlanRequest{})
+}
+
+// proposals that are currently in their voting period, fetching all pages
+func getVotingProposals(client *cosmosClient.Context) ([]govtypes.Proposal, error) {
+	var proposals []govtypes.Proposal
+	var nextKey []byte
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+		res, err := govtypes.NewQueryClient(client).Proposals(ctx, &govtypes.QueryProposalsRequest{
+			ProposalStatus: govtypes.StatusVotingPeriod,
+			Pagination:     &query.PageRequest{Key: nextKey},
+		})
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, res.Proposals...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return proposals, nil
+		}
+		nextKey = res.Pagination.NextKey
+	}
+}
+
+// Whether voter has voted on the proposal. The vote query returns an error when there is no vote,
+// which the gov module reports as an invalid argument and newer versions as not found.
+func getHasVoted(client *cosmosClient.Context, proposalID uint64, voter string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+	defer cancel()
+	_, err := govtypes.NewQueryClient(client).Vote(ctx, &govtypes.QueryVoteRequest{
+		ProposalId: proposalID,
+		Voter:      voter,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.InvalidArgument:
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// active validator set at height (latest if nil), fetching all pages. Validators are sorted by voting power.
+func getValidatorSet(node rpcclient.Client, height *int64) ([]*types.Validator, error) {
+	var validators []*types.Validator
+	perPage := validatorsPerPage
+	for page := 1; ; page++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+		result, err := node.Validators(ctx, height, &page, &perPage)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, result.Validators...)
+		if len(validators) >= result.Total || result.Count == 0 {
+			return validators, nil
+		}
+		// keep all pages at the same height in case a block is committed while paging
+		height = &result.BlockHeight
+	}
+}
+
+func getSentryInfo(grpcEndpoint Endpoint) (*tmservice.GetNodeInfoResponse, *tmservice.GetLatestBlockResponse, error) {
+	opts, err := grpcEndpoint.grpcDialOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := grpc.Dial(grpcEndpoint.URL, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -81,3 +162,18 @@ func getSentryInfo(grpcAddr string) (*tmservice.GetNodeInfoResponse, *tmservice.
 	}
 	return nodeInfo, syncingInfo, nil
 }
+
+func getSentryBlockByHeight(grpcEndpoint Endpoint, height int64) (*tmservice.GetBlockByHeightResponse, error) {
+	opts, err := grpcEndpoint.grpcDialOptions()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(grpcEndpoint.URL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*sentryGRPCTimeoutSeconds))
+	defer cancel()
+	return tmservice.NewServiceClient(conn).GetBlockByHeight(ctx, &tmservice.GetBlockByHeightRequest{Height: height})
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"sync"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -20,6 +20,8 @@ const (
 	sentryGRPCErrorNotifyThreshold                      = 1 // will notify with error for any more than this number of consecutive grpc errors for a given sentry
 	sentryOutOfSyncErrorNotifyThreshold                 = 1 // will notify with error for any more than this number of consecutive out of sync errors for a given sentry
 	sentryHaltErrorNotifyThreshold                      = 1 // will notify with error for any more than this number of consecutive halt errors for a given sentry
+	sentryCatchingUpErrorNotifyThreshold                = 1 // will notify with error for any more than this number of consecutive catching up errors for a given sentry
+	defaultNilVotesThreshold                    int64   = 1 // will notify for any more than this number of nil votes in the recent blocks checked
 )
 
 type AlertLevel int8
@@ -31,17 +33,73 @@ const (
 	alertLevelCritical
 )
 
+func (al AlertLevel) String() string {
+	switch al {
+	case alertLevelNone:
+		return "none"
+	case alertLevelWarning:
+		return "warning"
+	case alertLevelHigh:
+		return "high"
+	case alertLevelCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("AlertLevel(%d)", al)
+	}
+}
+
+func (al *AlertLevel) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	alertLevel := ""
+	err := unmarshal(&alertLevel)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range []AlertLevel{alertLevelNone, alertLevelWarning, alertLevelHigh, alertLevelCritical} {
+		if a.String() == alertLevel {
+			*al = a
+			return nil
+		}
+	}
+
+	return errors.New("Invalid AlertLevel")
+}
+
+func (al AlertLevel) MarshalYAML() (interface{}, error) {
+	return al.String(), nil
+}
+
 type AlertType string
 
 const (
-	alertTypeJailed             AlertType = "alertTypeJailed"
-	alertTypeTombstoned         AlertType = "alertTypeTombstoned"
-	alertTypeOutOfSync          AlertType = "alertTypeOutOfSync"
-	alertTypeBlockFetch         AlertType = "alertTypeBlockFetch"
-	alertTypeMissedRecentBlocks AlertType = "alertTypeMissedRecentBlocks"
-	alertTypeGenericRPC         AlertType = "alertTypeGenericRPC"
-	alertTypeHalt               AlertType = "alertTypeHalt"
-	alertTypeSlashingSLA        AlertType = "alertTypeSlashingSLA"
+	alertTypeJailed               AlertType = "alertTypeJailed"
+	alertTypeTombstoned           AlertType = "alertTypeTombstoned"
+	alertTypeOutOfSync            AlertType = "alertTypeOutOfSync"
+	alertTypeBlockFetch           AlertType = "alertTypeBlockFetch"
+	alertTypeMissedRecentBlocks   AlertType = "alertTypeMissedRecentBlocks"
+	alertTypeGenericRPC           AlertType = "alertTypeGenericRPC"
+	alertTypeHalt                 AlertType = "alertTypeHalt"
+	alertTypeSlashingSLA          AlertType = "alertTypeSlashingSLA"
+	alertTypeNilVotes             AlertType = "alertTypeNilVotes"
+	alertTypeInactive             AlertType = "alertTypeInactive"
+	alertTypeGovVote              AlertType = "alertTypeGovVote"
+	alertTypeUpgrade              AlertType = "alertTypeUpgrade"
+	alertTypeUpgradeHalt          AlertType = "alertTypeUpgradeHalt"
+	alertTypeSentryVersionDrift   AlertType = "alertTypeSentryVersionDrift"
+	alertTypeSentryMinVersion     AlertType = "alertTypeSentryMinVersion"
+	alertTypeSentryVersionChanged AlertType = "alertTypeSentryVersionChanged"
+	alertTypeDoubleSign           AlertType = "alertTypeDoubleSign"
+	alertTypeJailMargin           AlertType = "alertTypeJailMargin"
+	alertTypeNodeRPC              AlertType = "alertTypeNodeRPC"
+	alertTypeSentryConnections    AlertType = "alertTypeSentryConnections"
+	alertTypeUnexpectedPeers      AlertType = "alertTypeUnexpectedPeers"
+	alertTypeFork                 AlertType = "alertTypeFork"
+	alertTypeConsensusRound       AlertType = "alertTypeConsensusRound"
+	alertTypeConsensusStuck       AlertType = "alertTypeConsensusStuck"
+	alertTypeLowParticipation     AlertType = "alertTypeLowParticipation"
+	alertTypeSignatureLatency     AlertType = "alertTypeSignatureLatency"
+	alertTypeMissedProposals      AlertType = "alertTypeMissedProposals"
+	alertTypeValidatorConfig      AlertType = "alertTypeValidatorConfig"
 )
 
 var alertTypes = []AlertType{
@@ -53,6 +111,26 @@ var alertTypes = []AlertType{
 	alertTypeGenericRPC,
 	alertTypeHalt,
 	alertTypeSlashingSLA,
+	alertTypeNilVotes,
+	alertTypeInactive,
+	alertTypeGovVote,
+	alertTypeUpgrade,
+	alertTypeUpgradeHalt,
+	alertTypeSentryVersionDrift,
+	alertTypeSentryMinVersion,
+	alertTypeSentryVersionChanged,
+	alertTypeDoubleSign,
+	alertTypeJailMargin,
+	alertTypeNodeRPC,
+	alertTypeSentryConnections,
+	alertTypeUnexpectedPeers,
+	alertTypeFork,
+	alertTypeConsensusRound,
+	alertTypeConsensusStuck,
+	alertTypeLowParticipation,
+	alertTypeSignatureLatency,
+	alertTypeMissedProposals,
+	alertTypeValidatorConfig,
 }
 
 func (at *AlertType) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -85,19 +163,64 @@ const (
 	sentryAlertTypeGRPCError
 	sentryAlertTypeOutOfSyncError
 	sentryAlertTypeHalt
+	sentryAlertTypeCatchingUp
+	sentryAlertTypeLowPeers
+	sentryAlertTypeSyncInfo
 )
 
+func (t SentryAlertType) String() string {
+	switch t {
+	case sentryAlertTypeGRPCError:
+		return "grpc"
+	case sentryAlertTypeOutOfSyncError:
+		return "outOfSync"
+	case sentryAlertTypeHalt:
+		return "halt"
+	case sentryAlertTypeCatchingUp:
+		return "catchingUp"
+	case sentryAlertTypeLowPeers:
+		return "lowPeers"
+	case sentryAlertTypeSyncInfo:
+		return "syncInfo"
+	default:
+		return "none"
+	}
+}
+
+// stable identifier for an alert on a specific sentry
+func sentryAlertKey(sentry string, sentryAlertType SentryAlertType) string {
+	return fmt.Sprintf("%s-%s", sentry, sentryAlertType)
+}
+
+func sentryVersionChangedAlertKey(sentry string) string {
+	return fmt.Sprintf("%s-versionChanged", sentry)
+}
+
 type SentryStats struct {
 	Name            string
+	NodeID          string
 	Version         string
 	Height          int64
+	CatchingUp      bool
+	PeersChecked    bool // whether peers are known, from the rpc
+	Peers           int
 	SentryAlertType SentryAlertType
 }
 
 type ValidatorStats struct {
 	Timestamp                   time.Time
 	Height                      int64
-	RecentMissedBlocks          int64
+	RecentMissedBlocks          int64   // absent from the commit
+	RecentMissedHeights         []int64 // heights of the recent missed blocks, for confirming them with other rpc endpoints
+	RecentNilVotes              int64   // voted nil instead of for the block
+	RecentSignedBlocks          int64   // voted for the block
+	RecentNetworkMissedBlocks   int64   // missed blocks that many other validators also missed
+	ParticipationChecked        bool    // whether participation is known for the blocks checked
+	AverageParticipation        float64
+	MinParticipation            float64 // lowest percentage of voting power that signed a block checked
+	MinParticipationHeight      int64
+	SignatureLatency            *SignatureLatencyStats // only set when the validator signed a block checked
+	Proposals                   *ProposalStats         // only set when the recent blocks were checked
 	LastSignedBlockHeight       int64
 	RecentMissedBlockAlertLevel AlertLevel
 	LastSignedBlockTimestamp    time.Time
@@ -105,6 +228,28 @@ type ValidatorStats struct {
 	SentryStats                 []*SentryStats
 	AlertLevel                  AlertLevel
 	RPCError                    bool
+	RPCEndpoints                []*RPCEndpointStats // only set when there are multiple rpc endpoints
+	Topology                    *TopologyStats      // only set when the validator node peers are known
+	BlockHashes                 *BlockHashStats     // only set when there are sentries
+	Consensus                   *ConsensusStats     // only set when the consensus state is known
+	ActiveSetChecked            bool                // whether active set membership is known for this check
+	Inactive                    bool                // not in the active validator set, monitored as a full node
+	VotingPower                 int64               // voting power in the active set
+	ActiveSetRank               int                 // position in the active set by voting power, starting at 1
+	ActiveSetSize               int
+	GovChecked                  bool // whether proposals in their voting period are known for this check
+	GovProposals                []*GovProposalStats
+	JailMarginChecked           bool  // whether the jail margin is known for this check
+	JailMarginBlocks            int64 // blocks that can still be missed in the slashing window before jailing
+	JailMarginMaxMissed         int64 // blocks that can be missed in the slashing window
+	AverageBlockTime            time.Duration
+	UpgradeChecked              bool              // whether the upgrade plan is known for this check
+	UpgradePlan                 *UpgradePlanStats // nil if no upgrade is planned
+}
+
+// validators that are not in the active set are monitored as full nodes until they enter the active set
+func (stats ValidatorStats) fullNode(vm *ValidatorMonitor) bool {
+	return vm.FullNode || stats.Inactive
 }
 
 type ValidatorAlertState struct {
@@ -112,7 +257,22 @@ type ValidatorAlertState struct {
 	SentryGRPCErrorCounts        map[string]int64
 	SentryOutOfSyncErrorCounts   map[string]int64
 	SentryHaltErrorCounts        map[string]int64
+	SentryCatchingUpErrorCounts  map[string]int64
+	SentryLowPeersErrorCounts    map[string]int64
+	SentrySyncInfoErrorCounts    map[string]int64
 	SentryLatestHeight           map[string]int64
+	SentryVersions               map[string]string
+	SentryNodeIDs                map[string]string     // latest known node id of each sentry, for comparing against the validator node peers
+	ReportedEvidence             map[string]int64      // unix time that double sign evidence was alerted, by evidence hash
+	GovVoteAlertLevels           map[uint64]AlertLevel // highest alert level notified for each proposal without a vote
+	AlertKeyLevels               map[string]AlertLevel // highest alert level notified for each alert key until it is cleared, so clears reach the sinks that received the alert
+	JailMarginAlertLevel         AlertLevel            // highest alert level notified for the current jail margin
+	Inactive                     bool                  // whether leaving the active set has been alerted
+	UpgradePlanName              string                // upgrade plan that reminders have been sent for, kept so upgrade halts are known while the rpc node is down
+	UpgradePlanHeight            int64
+	UpgradeReminder              int    // latest reminder sent for the upgrade plan
+	ConsensusHeightRoundStep     string // latest consensus height/round/step
+	ConsensusChanged             int64  // unix time that the consensus height/round/step last changed
 	RecentMissedBlocksCounter    int64
 	RecentMissedBlocksCounterMax int64
 	LatestBlockChecked           int64
@@ -120,15 +280,79 @@ type ValidatorAlertState struct {
 }
 
 type ValidatorAlertNotification struct {
-	Alerts         []string
-	ClearedAlerts  []string
-	NotifyForClear bool
-	AlertLevel     AlertLevel
+	Alerts             []string
+	AlertKeys          []string     // stable identifier for each of Alerts, AlertType or sentry alert key
+	AlertLevels        []AlertLevel // level of each of Alerts, AlertLevel is the highest of them
+	ClearedAlerts      []string
+	ClearedAlertKeys   []string     // stable identifier for each of ClearedAlerts, matching the AlertKeys they were raised with
+	ClearedAlertLevels []AlertLevel // highest level each of ClearedAlerts was alerted at, none if unknown
+	NotifyForClear     bool
+	AlertLevel         AlertLevel
 }
 
 type NotificationsConfig struct {
-	Service string                `yaml:"service"`
-	Discord *DiscordChannelConfig `yaml:"discord"`
+	Name          string                `yaml:"name"`
+	Service       string                `yaml:"service"`
+	MinAlertLevel AlertLevel            `yaml:"min-alert-level"`
+	Validators    []string              `yaml:"validators"`
+	Chains        []string              `yaml:"chains"`
+	Discord       *DiscordChannelConfig `yaml:"discord"`
+	Slack         *SlackChannelConfig   `yaml:"slack"`
+	PagerDuty     *PagerDutyConfig      `yaml:"pagerduty"`
+}
+
+// sink receives notifications for the validator if it is not filtered out by validator name or chain ID
+func (n *NotificationsConfig) matches(vm *ValidatorMonitor) bool {
+	if len(n.Validators) > 0 && !containsString(n.Validators, vm.Name) {
+		return false
+	}
+	if len(n.Chains) > 0 && !containsString(n.Chains, vm.ChainID) {
+		return false
+	}
+	return true
+}
+
+// NotificationSinks is the list of notification services to fan out to.
+// A single mapping, as used before multiple sinks were supported, is accepted as a list of one.
+type NotificationSinks []*NotificationsConfig
+
+func (n *NotificationSinks) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	if _, isList := raw.([]interface{}); isList {
+		var sinks []*NotificationsConfig
+		if err := unmarshal(&sinks); err != nil {
+			return err
+		}
+		*n = sinks
+		return nil
+	}
+	sink := NotificationsConfig{}
+	if err := unmarshal(&sink); err != nil {
+		return err
+	}
+	*n = NotificationSinks{&sink}
+	return nil
+}
+
+func (n NotificationSinks) named(name string) *NotificationsConfig {
+	for _, sink := range n {
+		if sink.Name == name {
+			return sink
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
 }
 
 type AlertConfig struct {
@@ -145,13 +369,17 @@ func (at *AlertConfig) AlertActive(alert AlertType) bool {
 }
 
 type HalfLifeConfig struct {
-	AlertConfig   AlertConfig          `yaml:"alerts"`
-	Notifications *NotificationsConfig `yaml:"notifications"`
-	Validators    []*ValidatorMonitor  `yaml:"validators"`
+	AlertConfig   AlertConfig         `yaml:"alerts"`
+	Notifications NotificationSinks   `yaml:"notifications"`
+	Validators    []*ValidatorMonitor `yaml:"validators"`
 }
 
 func (c *HalfLifeConfig) getUnsetDefaults() {
-	fmt.Printf("%+v", *c.Notifications)
+	for _, sink := range c.Notifications {
+		if sink.Name == "" {
+			sink.Name = sink.Service
+		}
+	}
 	for idx := range c.Validators {
 		if c.Validators[idx].SlashingPeriodUptimeWarningThreshold == 0 {
 			c.Validators[idx].SlashingPeriodUptimeWarningThreshold = defaultSlashingPeriodUptimeWarningThreshold
@@ -171,6 +399,103 @@ func (c *HalfLifeConfig) getUnsetDefaults() {
 	}
 }
 
+func loadConfig(configFile string) (*HalfLifeConfig, error) {
+	dat, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading config.yaml: %w", err)
+	}
+	config := HalfLifeConfig{}
+	err = yaml.Unmarshal(dat, &config)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing config.yaml: %w", err)
+	}
+	config.getUnsetDefaults()
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *HalfLifeConfig) validate() error {
+	validatorNames := make(map[string]bool)
+	for _, vm := range c.Validators {
+		if vm.Name == "" {
+			return errors.New("Validator name not configured in config.yaml")
+		}
+		if validatorNames[vm.Name] {
+			return fmt.Errorf("Validator name is not unique: %s", vm.Name)
+		}
+		validatorNames[vm.Name] = true
+		if len(vm.rpcEndpoints()) == 0 {
+			return fmt.Errorf("No rpc configured for validator %s", vm.Name)
+		}
+		if vm.rpcQuorum() < 1 || vm.rpcQuorum() > len(vm.rpcEndpoints()) {
+			return fmt.Errorf("rpc-quorum for validator %s must be between 1 and the number of rpc endpoints (%d)", vm.Name, len(vm.rpcEndpoints()))
+		}
+		for _, rpc := range vm.rpcEndpoints() {
+			if err := rpc.validate(); err != nil {
+				return fmt.Errorf("Invalid rpc %s for validator %s: %w", getRPCEndpointName(rpc.URL), vm.Name, err)
+			}
+			if rpc.TLS.used() && !strings.HasPrefix(rpc.URL, "https://") {
+				return fmt.Errorf("Invalid rpc %s for validator %s: tls requires an https url", getRPCEndpointName(rpc.URL), vm.Name)
+			}
+			// the websocket client cannot send tls or authentication settings
+			if vm.StreamBlocks && rpc.hasOptions() {
+				return fmt.Errorf("Invalid rpc %s for validator %s: stream-blocks cannot be used with tls, headers or basic-auth settings", getRPCEndpointName(rpc.URL), vm.Name)
+			}
+		}
+		if vm.NodeRPC.URL != "" {
+			if err := vm.NodeRPC.validate(); err != nil {
+				return fmt.Errorf("Invalid node-rpc for validator %s: %w", vm.Name, err)
+			}
+		}
+		if vm.Sentries != nil {
+			for _, sentry := range *vm.Sentries {
+				if err := sentry.validate(); err != nil {
+					return fmt.Errorf("Invalid sentry %s of validator %s: %w", sentry.Name, vm.Name, err)
+				}
+			}
+		}
+		// participation at or below 2/3 would already stop blocks from being produced
+		if threshold := vm.participationThreshold(); threshold <= 200.0/3 || threshold > 100 {
+			return fmt.Errorf("participation-threshold for validator %s must be above 66.7 and at most 100", vm.Name)
+		}
+		if vm.OperatorAddress != "" {
+			if _, err := getGovVoterAddress(vm.OperatorAddress); err != nil {
+				return fmt.Errorf("Invalid operator-address for validator %s: %w", vm.Name, err)
+			}
+		}
+	}
+	return c.validateNotifications()
+}
+
+func (c *HalfLifeConfig) validateNotifications() error {
+	if len(c.Notifications) == 0 {
+		return errors.New("Notifications configuration is not present in config.yaml")
+	}
+	names := make(map[string]bool)
+	for _, sink := range c.Notifications {
+		if sink.Service == "" {
+			return errors.New("Notification service not configured in config.yaml")
+		}
+		if names[sink.Name] {
+			return fmt.Errorf("Notification name is not unique: %s", sink.Name)
+		}
+		names[sink.Name] = true
+		if sink.Service == "pagerduty" && sink.PagerDuty != nil && sink.PagerDuty.RoutingKey == "" {
+			return fmt.Errorf("PagerDuty routing-key not configured in config.yaml for notification %s", sink.Name)
+		}
+	}
+	for _, vm := range c.Validators {
+		for name := range vm.Discord {
+			if sink := c.Notifications.named(name); sink == nil || sink.Service != "discord" {
+				return fmt.Errorf("Discord override %s of validator %s is not the name of a discord notification", name, vm.Name)
+			}
+		}
+	}
+	return nil
+}
+
 type DiscordWebhookConfig struct {
 	ID    string `yaml:"id"`
 	Token string `yaml:"token"`
@@ -182,22 +507,54 @@ type DiscordChannelConfig struct {
 	Username     string               `yaml:"username"`
 }
 
+type SlackChannelConfig struct {
+	Token             string   `yaml:"token"`
+	Channel           string   `yaml:"channel"`
+	AlertUserGroupIDs []string `yaml:"alert-user-group-ids"`
+	APIURL            string   `yaml:"api-url"`
+}
+
+type PagerDutyConfig struct {
+	RoutingKey string `yaml:"routing-key"`
+	EventsURL  string `yaml:"events-url"`
+}
+
 type Sentry struct {
-	Name string `yaml:"name"`
-	GRPC string `yaml:"grpc"`
+	Name string   `yaml:"name"`
+	GRPC Endpoint `yaml:"grpc"`
+	RPC  Endpoint `yaml:"rpc"` // tendermint rpc, for catching up and peers
+	LCD  Endpoint `yaml:"lcd"` // rest api, for sentries without grpc or rpc
 }
 
 type ValidatorMonitor struct {
-	Name                     string    `yaml:"name"`
-	RPC                      string    `yaml:"rpc"`
-	FullNode                 bool      `yaml:"fullnode"`
-	Address                  string    `yaml:"address"`
-	ChainID                  string    `yaml:"chain-id"`
-	DiscordStatusMessageID   *string   `yaml:"discord-status-message-id"`
-	RPCRetries               *int      `yaml:"rpc-retries"`
-	MissedBlocksThreshold    *int64    `yaml:"missed-blocks-threshold"`
-	SentryGRPCErrorThreshold *int64    `yaml:"sentry-grpc-error-threshold"`
-	Sentries                 *[]Sentry `yaml:"sentries"`
+	Name                      string     `yaml:"name"`
+	RPC                       Endpoint   `yaml:"rpc"`
+	RPCs                      []Endpoint `yaml:"rpcs"`       // additional rpc endpoints to fail over to
+	RPCQuorum                 *int       `yaml:"rpc-quorum"` // rpc endpoints that must agree on missed blocks and jailed verdicts
+	FullNode                  bool       `yaml:"fullnode"`
+	StreamBlocks              bool       `yaml:"stream-blocks"` // subscribe to new blocks over the RPC websocket instead of fetching recent blocks each check
+	Address                   string     `yaml:"address"`
+	OperatorAddress           string     `yaml:"operator-address"` // enables governance vote monitoring
+	NodeRPC                   Endpoint   `yaml:"node-rpc"`         // private rpc of the validator node, enables sentry connection monitoring
+	AllowedPeers              []string   `yaml:"allowed-peers"`    // node ids the validator node may be connected to besides its sentries
+	SignerLabels              []string   `yaml:"signer-labels"`    // double sign evidence is also alerted for validators sharing a label
+	ChainID                   string     `yaml:"chain-id"`
+	DiscordStatusMessageID    *string    `yaml:"discord-status-message-id"` // deprecated, migrated to the state file
+	SlackStatusMessageTS      *string    `yaml:"slack-status-message-ts"`   // deprecated, migrated to the state file
+	RPCRetries                *int       `yaml:"rpc-retries"`
+	MissedBlocksThreshold     *int64     `yaml:"missed-blocks-threshold"`
+	NilVotesThreshold         *int64     `yaml:"nil-votes-threshold"`
+	SentryGRPCErrorThreshold  *int64     `yaml:"sentry-grpc-error-threshold"`
+	Sentries                  *[]Sentry  `yaml:"sentries"`
+	SentryMinVersion          string     `yaml:"sentry-min-version"`
+	SentryMinPeers            *int       `yaml:"sentry-min-peers"`
+	ConsensusRoundThreshold   *int32     `yaml:"consensus-round-threshold"`
+	ParticipationThreshold    *float64   `yaml:"participation-threshold"`
+	SignatureLatencyThreshold *int64     `yaml:"signature-latency-threshold"` // milliseconds
+	MissedProposalsThreshold  *int64     `yaml:"missed-proposals-threshold"`
+
+	// overrides for the discord notification settings keyed by notification name, unset values fall back to the notifications config
+	Discord map[string]*DiscordChannelConfig `yaml:"discord,omitempty"`
 
 	SlashingPeriodUptimeWarningThreshold float64 `yaml:"slashing_warn_threshold"`
 	SlashingPeriodUptimeErrorThreshold   float64 `yaml:"slashing_error_threshold"`
@@ -205,18 +562,3 @@ type ValidatorMonitor struct {
 	NotifyEvery                          int64   `yaml:"notify_every"`
 	RecentMissedBlocksNotifyThreshold    int64   `yaml:"recent_missed_blocks_notify_threshold"`
 }
-
-func saveConfig(configFile string, config *HalfLifeConfig, writeConfigMutex *sync.Mutex) {
-	writeConfigMutex.Lock()
-	defer writeConfigMutex.Unlock()
-
-	yamlBytes, err := yaml.Marshal(config)
-	if err != nil {
-		fmt.Printf("Error during config yaml marshal %v\n", err)
-	}
-
-	err = os.WriteFile(configFile, yamlBytes, 0600)
-	if err != nil {
-		fmt.Printf("Error saving config yaml %v\n", err)
-	}
-}
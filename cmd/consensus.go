@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	cstypes "github.com/tendermint/tendermint/consensus/types"
+)
+
+const (
+	defaultConsensusRoundThreshold int32 = 3
+	consensusStuckThreshold              = time.Minute // consensus steps time out within seconds, so an unchanged step for this long is stuck
+)
+
+// voting power that has voted, from the vote bit array summary "BA{4:xx__} 200/400 = 0.50"
+var voteBitArrayRegexp = regexp.MustCompile(`(\d+)/(\d+) = [\d.]+$`)
+
+type ConsensusStats struct {
+	Height     int64
+	Round      int32
+	Step       string
+	Prevotes   float64 // percentage of voting power that prevoted in the current round
+	Precommits float64 // percentage of voting power that precommitted in the current round
+	StuckFor   time.Duration
+}
+
+type consensusRoundState struct {
+	HeightRoundStep string `json:"height/round/step"`
+	Votes           []struct {
+		Round              int32  `json:"round"`
+		PrevotesBitArray   string `json:"prevotes_bit_array"`
+		PrecommitsBitArray string `json:"precommits_bit_array"`
+	} `json:"height_vote_set"`
+}
+
+func (vm *ValidatorMonitor) consensusRoundThreshold() int32 {
+	if vm.ConsensusRoundThreshold == nil {
+		return defaultConsensusRoundThreshold
+	}
+	return *vm.ConsensusRoundThreshold
+}
+
+// node to poll the consensus state from, the validator node when its rpc is configured
+func (vm *ValidatorMonitor) consensusRPC(rpc Endpoint) Endpoint {
+	if vm.NodeRPC.URL != "" {
+		return vm.NodeRPC
+	}
+	return rpc
+}
+
+func getStepName(step int) string {
+	return strings.TrimPrefix(cstypes.RoundStepType(step).String(), "RoundStep")
+}
+
+func getVotedPercentage(bitArray string) float64 {
+	match := voteBitArrayRegexp.FindStringSubmatch(bitArray)
+	if match == nil {
+		return 0
+	}
+	voted, _ := strconv.ParseInt(match[1], 10, 64)
+	total, _ := strconv.ParseInt(match[2], 10, 64)
+	if total == 0 {
+		return 0
+	}
+	return float64(voted) / float64(total) * 100
+}
+
+// current height, round and step from /consensus_state, with the votes of the current round
+func getConsensusStats(rpc Endpoint) (*ConsensusStats, error) {
+	client, err := newClient(rpc)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+	defer cancel()
+	result, err := client.ConsensusState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var roundState consensusRoundState
+	if err := json.Unmarshal(result.RoundState, &roundState); err != nil {
+		return nil, err
+	}
+	parts := strings.Split(roundState.HeightRoundStep, "/")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid height/round/step: %s", roundState.HeightRoundStep)
+	}
+	height, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	round, err := strconv.ParseInt(parts[1], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	step, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	consensus := &ConsensusStats{Height: height, Round: int32(round), Step: getStepName(step)}
+	for _, votes := range roundState.Votes {
+		if votes.Round == consensus.Round {
+			consensus.Prevotes = getVotedPercentage(votes.PrevotesBitArray)
+			consensus.Precommits = getVotedPercentage(votes.PrecommitsBitArray)
+			break
+		}
+	}
+	return consensus, nil
+}
+
+// requires locked alertState. Tracks how long the height, round and step have been unchanged.
+func (a *ValidatorAlertState) trackConsensus(consensus *ConsensusStats) {
+	heightRoundStep := fmt.Sprintf("%d/%d/%s", consensus.Height, consensus.Round, consensus.Step)
+	if heightRoundStep != a.ConsensusHeightRoundStep {
+		a.ConsensusHeightRoundStep = heightRoundStep
+		a.ConsensusChanged = time.Now().Unix()
+		return
+	}
+	consensus.StuckFor = time.Since(time.Unix(a.ConsensusChanged, 0))
+}
+
+func getConsensusErrors(vm *ValidatorMonitor, stats *ValidatorStats, alertConfig AlertConfig) (errs []error) {
+	consensus := stats.Consensus
+	if consensus == nil {
+		return
+	}
+	var err IgnorableError
+	if consensus.StuckFor > consensusStuckThreshold {
+		err = newConsensusStuckError(consensus)
+	} else if consensus.Round > vm.consensusRoundThreshold() {
+		err = newConsensusRoundError(consensus)
+	}
+	if err != nil && err.Active(alertConfig) {
+		stats.increaseAlertLevel(alertLevelHigh)
+		errs = append(errs, err)
+	}
+	return
+}
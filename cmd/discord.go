@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -22,23 +23,25 @@ const (
 	iconGood    = "🟢" // green circle
 	iconWarning = "🟡" // yellow circle
 	iconError   = "🔴" // red circle
+
+	discordErrorUnknownMessage discord.ErrorCode = 10008
 )
 
 type DiscordNotificationService struct {
-	webhookID    string
-	webhookToken string
-	postMutex    *sync.Mutex
+	name      string
+	config    *DiscordChannelConfig
+	postMutex *sync.Mutex
 }
 
 func formattedTime(t time.Time) string {
 	return fmt.Sprintf("<t:%d:R>", t.Unix())
 }
 
-func NewDiscordNotificationService(webhookID, webhookToken string) *DiscordNotificationService {
+func NewDiscordNotificationService(name string, config *DiscordChannelConfig) *DiscordNotificationService {
 	return &DiscordNotificationService{
-		webhookID:    webhookID,
-		webhookToken: webhookToken,
-		postMutex:    &sync.Mutex{},
+		name:      name,
+		config:    config,
+		postMutex: &sync.Mutex{},
 	}
 }
 
@@ -55,104 +58,13 @@ func getColorForAlertLevel(alertLevel AlertLevel) int {
 	}
 }
 
-func getCurrentStatsEmbed(stats ValidatorStats, vm *ValidatorMonitor) discord.Embed {
-	var uptime string
-	var title string
-	if vm.FullNode {
-		title = vm.Name
-	} else {
-		if stats.SlashingPeriodUptime == 0 {
-			uptime = "N/A"
-		} else {
-			uptime = fmt.Sprintf("%.02f", stats.SlashingPeriodUptime)
-		}
-
-		title = fmt.Sprintf("%s (%s%% up)", vm.Name, uptime)
-	}
-
-	var description string
-	sentryString := ""
-
-	if vm.Sentries != nil {
-		for _, vmSentry := range *vm.Sentries {
-			sentryFound := false
-			for _, sentryStats := range stats.SentryStats {
-				if vmSentry.Name == sentryStats.Name {
-					var statusIcon string
-					if sentryStats.SentryAlertType == sentryAlertTypeNone {
-						statusIcon = iconGood
-					} else {
-						statusIcon = iconError
-					}
-
-					var height string
-					if sentryStats.Height == 0 {
-						height = "N/A"
-					} else {
-						height = fmt.Sprint(sentryStats.Height)
-					}
-					var version string
-					if sentryStats.Version == "" {
-						version = "N/A"
-					} else {
-						version = sentryStats.Version
-					}
-
-					sentryString += fmt.Sprintf("\n%s **%s** - Height **%s** - Version **%s**", statusIcon, sentryStats.Name, height, version)
-					sentryFound = true
-					break
-				}
-			}
-			if !sentryFound {
-				sentryString += fmt.Sprintf("\n%s **%s** - Height **N/A** - Version **N/A**", iconError, vmSentry.Name)
-			}
-		}
-	}
-
-	recentSignedBlocks := fmt.Sprintf("%s Latest Blocks Signed: **N/A**", iconWarning)
-
-	var latestBlock string
-	if stats.Timestamp.Before(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)) {
-		latestBlock = fmt.Sprintf("%s Height **N/A**", iconError)
-	} else {
-		var rpcStatusIcon string
-		if stats.RPCError {
-			rpcStatusIcon = iconError
-		} else {
-			rpcStatusIcon = iconGood
-			if !vm.FullNode {
-				var recentSignedBlocksIcon string
-				switch level := stats.RecentMissedBlockAlertLevel; {
-				case level >= alertLevelHigh:
-					recentSignedBlocksIcon = iconError
-				case level == alertLevelWarning:
-					recentSignedBlocksIcon = iconWarning
-				default:
-					recentSignedBlocksIcon = iconGood
-				}
-				recentSignedBlocks = fmt.Sprintf("%s Latest Blocks Signed: **%d/%d**", recentSignedBlocksIcon, vm.RecentBlocksToCheck-stats.RecentMissedBlocks, vm.RecentBlocksToCheck)
-			}
-		}
-		latestBlock = fmt.Sprintf("%s Height **%s** - **%s**", rpcStatusIcon, fmt.Sprint(stats.Height), formattedTime(stats.Timestamp))
-	}
+var discordStatusFormat = statusFormat{
+	bold:      func(s string) string { return fmt.Sprintf("**%s**", s) },
+	timestamp: formattedTime,
+}
 
-	if vm.FullNode {
-		description = fmt.Sprintf("%s%s", latestBlock, sentryString)
-	} else {
-		if stats.Height == stats.LastSignedBlockHeight {
-			description = fmt.Sprintf("%s\n%s%s",
-				latestBlock, recentSignedBlocks, sentryString)
-		} else {
-			var lastSignedBlock string
-			if stats.LastSignedBlockHeight == -1 {
-				lastSignedBlock = fmt.Sprintf("%s Last Signed **N/A**", iconError)
-			} else {
-				lastSignedBlock = fmt.Sprintf("%s Last Signed **%s** - **%s**", iconError, fmt.Sprint(stats.LastSignedBlockHeight), formattedTime(stats.LastSignedBlockTimestamp))
-			}
-			description = fmt.Sprintf("%s\n%s\n%s%s",
-				latestBlock, lastSignedBlock, recentSignedBlocks, sentryString)
-		}
-	}
+func getCurrentStatsEmbed(stats ValidatorStats, vm *ValidatorMonitor) discord.Embed {
+	title, description := getCurrentStatsTitleAndDescription(stats, vm, discordStatusFormat)
 
 	color := getColorForAlertLevel(stats.AlertLevel)
 
@@ -163,52 +75,84 @@ func getCurrentStatsEmbed(stats ValidatorStats, vm *ValidatorMonitor) discord.Em
 	}
 }
 
-func (service *DiscordNotificationService) client() *webhook.Client {
-	return webhook.NewClient(snowflake.Snowflake(service.webhookID), service.webhookToken)
+// discord settings for the validator, with any validator overrides for this notification applied over the notifications config
+func (service *DiscordNotificationService) channelConfig(vm *ValidatorMonitor) DiscordChannelConfig {
+	channelConfig := *service.config
+	override, ok := vm.Discord[service.name]
+	if !ok || override == nil {
+		return channelConfig
+	}
+	if override.Webhook.ID != "" {
+		channelConfig.Webhook = override.Webhook
+	}
+	if override.Username != "" {
+		channelConfig.Username = override.Username
+	}
+	if len(override.AlertUserIDs) > 0 {
+		channelConfig.AlertUserIDs = override.AlertUserIDs
+	}
+	return channelConfig
+}
+
+func (channelConfig DiscordChannelConfig) client() *webhook.Client {
+	return webhook.NewClient(snowflake.Snowflake(channelConfig.Webhook.ID), channelConfig.Webhook.Token)
 }
 
 // implements NotificationService interface
 func (service *DiscordNotificationService) UpdateValidatorRealtimeStatus(
-	configFile string,
 	config *HalfLifeConfig,
 	vm *ValidatorMonitor,
 	stats ValidatorStats,
-	writeConfigMutex *sync.Mutex,
+	stateStore *StateStore,
 ) {
+	channelConfig := service.channelConfig(vm)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*4))
 	defer cancel()
-	client := service.client()
+	client := channelConfig.client()
 	defer client.Close(ctx)
-	if vm.DiscordStatusMessageID != nil {
+	if statusMessageID, ok := stateStore.statusMessageID(vm.Name, service.name); ok {
 		service.postMutex.Lock()
-		_, err := client.UpdateMessage(snowflake.Snowflake(*vm.DiscordStatusMessageID), discord.WebhookMessageUpdate{
+		_, err := client.UpdateMessage(snowflake.Snowflake(statusMessageID), discord.WebhookMessageUpdate{
 			Embeds: &[]discord.Embed{
 				getCurrentStatsEmbed(stats, vm),
 			},
 		}, rest.WithCtx(ctx))
 		service.postMutex.Unlock()
-		if err != nil {
-			fmt.Printf("Error updating discord message: %v\n", err)
+		if err == nil {
 			return
 		}
-	} else {
-		service.postMutex.Lock()
-		message, err := client.CreateMessage(discord.WebhookMessageCreate{
-			Username: config.Notifications.Discord.Username,
-			Embeds: []discord.Embed{
-				getCurrentStatsEmbed(stats, vm),
-			},
-		}, rest.WithCtx(ctx))
-		service.postMutex.Unlock()
-		if err != nil {
-			fmt.Printf("Error sending discord message: %v\n", err)
+		fmt.Printf("Error updating discord message: %v\n", err)
+		// the status message was deleted or was posted by another webhook, so a new one is posted in its place.
+		// Other errors may be temporary, so the update is retried on the next check.
+		if !isDiscordMessageNotFound(err) {
 			return
 		}
-		messageID := string(message.ID)
-		vm.DiscordStatusMessageID = &messageID
-		fmt.Printf("Saved message ID: %s\n", messageID)
-		saveConfig(configFile, config, writeConfigMutex)
 	}
+
+	service.postMutex.Lock()
+	message, err := client.CreateMessage(discord.WebhookMessageCreate{
+		Username: channelConfig.Username,
+		Embeds: []discord.Embed{
+			getCurrentStatsEmbed(stats, vm),
+		},
+	}, rest.WithCtx(ctx))
+	service.postMutex.Unlock()
+	if err != nil {
+		fmt.Printf("Error sending discord message: %v\n", err)
+		return
+	}
+	messageID := string(message.ID)
+	fmt.Printf("Saved message ID: %s\n", messageID)
+	stateStore.saveStatusMessageID(vm.Name, service.name, messageID)
+}
+
+// discord responds with 404 Unknown Message when the message does not exist for the webhook
+func isDiscordMessageNotFound(err error) bool {
+	restErr, ok := err.(*rest.Error)
+	if !ok {
+		return false
+	}
+	return restErr.Code == discordErrorUnknownMessage || (restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound)
 }
 
 // implements NotificationService interface
@@ -217,23 +161,26 @@ func (service *DiscordNotificationService) SendValidatorAlertNotification(
 	vm *ValidatorMonitor,
 	stats ValidatorStats,
 	alertNotification *ValidatorAlertNotification,
-) {
+) error {
+	return service.sendAlertNotification(service.channelConfig(vm), getAlertTitle(stats, vm), alertNotification)
+}
+
+// implements NotificationService interface
+func (service *DiscordNotificationService) SendAlertNotification(title string, alertNotification *ValidatorAlertNotification) error {
+	return service.sendAlertNotification(*service.config, title, alertNotification)
+}
+
+func (service *DiscordNotificationService) sendAlertNotification(
+	channelConfig DiscordChannelConfig,
+	embedTitle string,
+	alertNotification *ValidatorAlertNotification,
+) error {
+	var sendErr error
 	tagUser := ""
-	for _, userID := range config.Notifications.Discord.AlertUserIDs {
+	for _, userID := range channelConfig.AlertUserIDs {
 		tagUser += fmt.Sprintf("<@%s> ", userID)
 	}
 
-	var embedTitle string
-	if vm.FullNode {
-		embedTitle = vm.Name
-	} else {
-		if stats.SlashingPeriodUptime > 0 {
-			embedTitle = fmt.Sprintf("%s (%.02f%% up)", vm.Name, stats.SlashingPeriodUptime)
-		} else {
-			embedTitle = fmt.Sprintf("%s (N/A%% up)", vm.Name)
-		}
-	}
-
 	if len(alertNotification.Alerts) > 0 {
 		alertString := ""
 		for _, alert := range alertNotification.Alerts {
@@ -246,11 +193,11 @@ func (service *DiscordNotificationService) SendValidatorAlertNotification(
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*4))
 		defer cancel()
-		client := service.client()
+		client := channelConfig.client()
 		defer client.Close(ctx)
 		service.postMutex.Lock()
 		_, err := client.CreateMessage(discord.WebhookMessageCreate{
-			Username: config.Notifications.Discord.Username,
+			Username: channelConfig.Username,
 			Content:  toNotify,
 			Embeds: []discord.Embed{
 				discord.Embed{
@@ -262,7 +209,7 @@ func (service *DiscordNotificationService) SendValidatorAlertNotification(
 		}, rest.WithCtx(ctx))
 		service.postMutex.Unlock()
 		if err != nil {
-			fmt.Printf("Error sending discord message: %v\n", err)
+			sendErr = err
 		}
 	}
 
@@ -277,11 +224,11 @@ func (service *DiscordNotificationService) SendValidatorAlertNotification(
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*4))
 		defer cancel()
-		client := service.client()
+		client := channelConfig.client()
 		defer client.Close(ctx)
 		service.postMutex.Lock()
 		_, err := client.CreateMessage(discord.WebhookMessageCreate{
-			Username: config.Notifications.Discord.Username,
+			Username: channelConfig.Username,
 			Content:  toNotify,
 			Embeds: []discord.Embed{
 				discord.Embed{
@@ -293,7 +240,8 @@ func (service *DiscordNotificationService) SendValidatorAlertNotification(
 		}, rest.WithCtx(ctx))
 		service.postMutex.Unlock()
 		if err != nil {
-			fmt.Printf("Error sending discord message: %v\n", err)
+			sendErr = err
 		}
 	}
+	return sendErr
 }
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Endpoint is a grpc or rpc address with optional TLS and authentication settings.
+// A plain address is accepted in the config for endpoints without settings.
+type Endpoint struct {
+	URL       string            `yaml:"url"`
+	TLS       *EndpointTLS      `yaml:"tls"`
+	Headers   map[string]string `yaml:"headers"` // sent with every request, e.g. for provider API keys
+	BasicAuth *BasicAuth        `yaml:"basic-auth"`
+}
+
+type EndpointTLS struct {
+	Enabled            bool   `yaml:"enabled"`              // use TLS with the system roots, implied by the other settings
+	CAFile             string `yaml:"ca-file"`              // custom CA to verify the server certificate
+	CertFile           string `yaml:"cert-file"`            // client certificate
+	KeyFile            string `yaml:"key-file"`             // client certificate key
+	ServerName         string `yaml:"server-name"`          // overrides the server name used to verify the certificate
+	InsecureSkipVerify bool   `yaml:"insecure-skip-verify"` // do not verify the server certificate
+}
+
+type BasicAuth struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+func (e *Endpoint) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var url string
+	if err := unmarshal(&url); err == nil {
+		*e = Endpoint{URL: url}
+		return nil
+	}
+	type endpoint Endpoint
+	return unmarshal((*endpoint)(e))
+}
+
+func (t *EndpointTLS) used() bool {
+	return t != nil && (t.Enabled || t.CAFile != "" || t.CertFile != "" || t.ServerName != "" || t.InsecureSkipVerify)
+}
+
+// whether the endpoint needs settings beyond its url
+func (e Endpoint) hasOptions() bool {
+	return e.TLS.used() || len(e.Headers) > 0 || e.BasicAuth != nil
+}
+
+func (e Endpoint) validate() error {
+	if e.URL == "" {
+		return errors.New("url is not configured")
+	}
+	if e.TLS.used() && (e.TLS.CertFile == "") != (e.TLS.KeyFile == "") {
+		return errors.New("tls cert-file and key-file must be configured together")
+	}
+	if e.BasicAuth != nil && e.BasicAuth.Username == "" {
+		return errors.New("basic-auth username is not configured")
+	}
+	if _, err := e.tlsConfig(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TLS config for the endpoint, nil if TLS is not configured
+func (e Endpoint) tlsConfig() (*tls.Config, error) {
+	if !e.TLS.used() {
+		return nil, nil
+	}
+	config := &tls.Config{
+		ServerName:         e.TLS.ServerName,
+		InsecureSkipVerify: e.TLS.InsecureSkipVerify, // #nosec G402 opt-in for self-signed sentries
+	}
+	if e.TLS.CAFile != "" {
+		ca, err := ioutil.ReadFile(e.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading tls ca-file: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("No certificates found in tls ca-file: %s", e.TLS.CAFile)
+		}
+	}
+	if e.TLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(e.TLS.CertFile, e.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading tls client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+func (e Endpoint) authorization() string {
+	if e.BasicAuth == nil {
+		return ""
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte(e.BasicAuth.Username + ":" + e.BasicAuth.Password))
+	return "Basic " + credentials
+}
+
+// endpointTransport adds the endpoint headers and basic auth to rpc requests
+type endpointTransport struct {
+	endpoint Endpoint
+	base     http.RoundTripper
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for key, value := range t.endpoint.Headers {
+		req.Header.Set(key, value)
+	}
+	if authorization := t.endpoint.authorization(); authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// applies the endpoint settings to an rpc http client
+func (e Endpoint) configureHTTPClient(httpClient *http.Client) error {
+	if !e.hasOptions() {
+		return nil
+	}
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		return errors.New("Unexpected rpc http transport")
+	}
+	tlsConfig, err := e.tlsConfig()
+	if err != nil {
+		return err
+	}
+	transport.TLSClientConfig = tlsConfig
+	httpClient.Transport = &endpointTransport{endpoint: e, base: transport}
+	return nil
+}
+
+// endpointCredentials adds the endpoint headers and basic auth to grpc requests
+type endpointCredentials struct {
+	endpoint Endpoint
+}
+
+func (c endpointCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for key, value := range c.endpoint.Headers {
+		metadata[strings.ToLower(key)] = value
+	}
+	if authorization := c.endpoint.authorization(); authorization != "" {
+		metadata["authorization"] = authorization
+	}
+	return metadata, nil
+}
+
+// headers are also allowed without TLS, e.g. for sentries on a private network
+func (c endpointCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// grpc dial options for the endpoint settings
+func (e Endpoint) grpcDialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+	tlsConfig, err := e.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	if len(e.Headers) > 0 || e.BasicAuth != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(endpointCredentials{endpoint: e}))
+	}
+	return opts, nil
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"math"
+	"strings"
 	"time"
 )
 
@@ -21,7 +22,7 @@ func (e *ignoreableError) Error() string {
 	return e.err.Error()
 }
 func (e *ignoreableError) Active(config AlertConfig) bool {
-	return true
+	return config.AlertActive(alertTypeValidatorConfig)
 }
 func newIgnorableError(err error) *ignoreableError {
 	return &ignoreableError{err}
@@ -49,6 +50,46 @@ func newTombstonedError() *TombstonedError {
 	return &TombstonedError{}
 }
 
+type InactiveError struct{}
+
+func (e *InactiveError) Error() string {
+	return "validator is not in the active set, monitoring as full node"
+}
+func (e *InactiveError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeInactive)
+}
+func newInactiveError() *InactiveError {
+	return &InactiveError{}
+}
+
+type DoubleSignError struct{ evidence *doubleSignEvidence }
+
+func (e *DoubleSignError) Error() string {
+	return fmt.Sprintf("double sign evidence committed in block %d: %s", e.evidence.Height, e.evidence.Details)
+}
+func (e *DoubleSignError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeDoubleSign)
+}
+func newDoubleSignError(evidence *doubleSignEvidence) *DoubleSignError {
+	return &DoubleSignError{evidence}
+}
+
+type SignerDoubleSignError struct {
+	evidence *doubleSignEvidence
+	labels   []string
+}
+
+func (e *SignerDoubleSignError) Error() string {
+	return fmt.Sprintf("double sign evidence against %s (%s) sharing signer %s, committed in block %d: %s",
+		e.evidence.Validator, e.evidence.ChainID, strings.Join(e.labels, ", "), e.evidence.Height, e.evidence.Details)
+}
+func (e *SignerDoubleSignError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeDoubleSign)
+}
+func newSignerDoubleSignError(evidence *doubleSignEvidence, labels []string) *SignerDoubleSignError {
+	return &SignerDoubleSignError{evidence, labels}
+}
+
 type OutOfSyncError struct{ msg string }
 
 func (e *OutOfSyncError) Error() string { return e.msg }
@@ -74,6 +115,42 @@ func newChainHaltError(durationNano int64) *ChainHaltError {
 	return &ChainHaltError{durationNano: durationNano}
 }
 
+type UpgradeReminderError struct {
+	name          string
+	height        int64
+	estimatedTime time.Time
+}
+
+func (e *UpgradeReminderError) Error() string {
+	if e.estimatedTime.IsZero() {
+		return fmt.Sprintf("upgrade %s scheduled at height %d", e.name, e.height)
+	}
+	return fmt.Sprintf("upgrade %s scheduled at height %d, estimated in %s (%s)", e.name, e.height, formatDurationHours(time.Until(e.estimatedTime)), e.estimatedTime.UTC().Format(time.RFC1123))
+}
+func (e *UpgradeReminderError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeUpgrade)
+}
+func newUpgradeReminderError(name string, height int64, estimatedTime time.Time) *UpgradeReminderError {
+	return &UpgradeReminderError{name, height, estimatedTime}
+}
+
+type UpgradeHaltError struct {
+	name         string
+	height       int64
+	durationNano int64
+}
+
+func (e *UpgradeHaltError) Error() string {
+	minutesHalted := int64(math.Round(float64(e.durationNano) / 6e10))
+	return fmt.Sprintf("rpc node has been halted for %dmin at upgrade %s height %d, waiting for blocks to resume", minutesHalted, e.name, e.height)
+}
+func (e *UpgradeHaltError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeUpgradeHalt)
+}
+func newUpgradeHaltError(name string, height int64, durationNano int64) *UpgradeHaltError {
+	return &UpgradeHaltError{name, height, durationNano}
+}
+
 type BlockFetchError struct {
 	height  int64
 	address string
@@ -90,18 +167,46 @@ func newBlockFetchError(height int64, address string) *BlockFetchError {
 }
 
 type MissedRecentBlocksError struct {
-	missed  int64
-	toCheck int64
+	missed      int64
+	toCheck     int64
+	networkWide int64 // missed blocks that many other validators also missed
 }
 
 func (e *MissedRecentBlocksError) Error() string {
-	return fmt.Sprintf("missed %d/%d most recent blocks", e.missed, e.toCheck)
+	switch {
+	case e.networkWide == 0:
+		return fmt.Sprintf("missed %d/%d most recent blocks", e.missed, e.toCheck)
+	case e.networkWide >= e.missed:
+		return fmt.Sprintf("missed %d/%d most recent blocks, all during network-wide misses", e.missed, e.toCheck)
+	default:
+		return fmt.Sprintf("missed %d/%d most recent blocks, %d during network-wide misses", e.missed, e.toCheck, e.networkWide)
+	}
+}
+
+// misses that were all network-wide are not the validator's problem alone, so they are only a warning
+func (e *MissedRecentBlocksError) onlyNetworkWide() bool {
+	return e.networkWide >= e.missed
 }
 func (e *MissedRecentBlocksError) Active(config AlertConfig) bool {
 	return config.AlertActive(alertTypeMissedRecentBlocks)
 }
-func newMissedRecentBlocksError(missed, toCheck int64) *MissedRecentBlocksError {
-	return &MissedRecentBlocksError{missed, toCheck}
+func newMissedRecentBlocksError(missed, toCheck, networkWide int64) *MissedRecentBlocksError {
+	return &MissedRecentBlocksError{missed, toCheck, networkWide}
+}
+
+type NilVotesError struct {
+	votes   int64
+	toCheck int64
+}
+
+func (e *NilVotesError) Error() string {
+	return fmt.Sprintf("voted nil for %d/%d most recent blocks", e.votes, e.toCheck)
+}
+func (e *NilVotesError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeNilVotes)
+}
+func newNilVotesError(votes, toCheck int64) *NilVotesError {
+	return &NilVotesError{votes, toCheck}
 }
 
 type SlashingSLAError struct {
@@ -119,6 +224,184 @@ func newSlashingSLAError(uptime, sla float64) *SlashingSLAError {
 	return &SlashingSLAError{uptime, sla}
 }
 
+type GovVoteError struct {
+	proposalID    uint64
+	title         string
+	votingEndTime time.Time
+}
+
+func (e *GovVoteError) Error() string {
+	return fmt.Sprintf("has not voted on proposal #%d (%s), voting ends in %s", e.proposalID, e.title, formatDurationHours(time.Until(e.votingEndTime)))
+}
+func (e *GovVoteError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeGovVote)
+}
+func (e *GovVoteError) alertLevel() AlertLevel {
+	return getGovVoteAlertLevel(e.votingEndTime)
+}
+func newGovVoteError(proposalID uint64, title string, votingEndTime time.Time) *GovVoteError {
+	return &GovVoteError{proposalID, title, votingEndTime}
+}
+
+type JailMarginError struct {
+	blocks    int64
+	maxMissed int64
+	estimate  time.Duration
+}
+
+func (e *JailMarginError) Error() string {
+	if e.estimate == 0 {
+		return fmt.Sprintf("%d/%d missed blocks left in slashing window before jailing", e.blocks, e.maxMissed)
+	}
+	return fmt.Sprintf("%d/%d missed blocks left in slashing window before jailing, jailed in about %s if blocks continue to be missed", e.blocks, e.maxMissed, formatDurationHours(e.estimate))
+}
+func (e *JailMarginError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeJailMargin)
+}
+func (e *JailMarginError) alertLevel() AlertLevel {
+	return getJailMarginAlertLevel(e.blocks, e.maxMissed)
+}
+func newJailMarginError(blocks int64, maxMissed int64, estimate time.Duration) *JailMarginError {
+	return &JailMarginError{blocks, maxMissed, estimate}
+}
+
+type NodeRPCError struct{ msg string }
+
+func (e *NodeRPCError) Error() string { return fmt.Sprintf("validator node rpc error - %s", e.msg) }
+func (e *NodeRPCError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeNodeRPC)
+}
+func newNodeRPCError(msg string) *NodeRPCError {
+	return &NodeRPCError{msg}
+}
+
+type ConsensusRoundError struct{ consensus *ConsensusStats }
+
+func (e *ConsensusRoundError) Error() string {
+	c := e.consensus
+	return fmt.Sprintf("consensus is at round %d for height %d, step %s with %.0f%% prevotes and %.0f%% precommits", c.Round, c.Height, c.Step, c.Prevotes, c.Precommits)
+}
+func (e *ConsensusRoundError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeConsensusRound)
+}
+func newConsensusRoundError(consensus *ConsensusStats) *ConsensusRoundError {
+	return &ConsensusRoundError{consensus}
+}
+
+type ConsensusStuckError struct{ consensus *ConsensusStats }
+
+func (e *ConsensusStuckError) Error() string {
+	c := e.consensus
+	return fmt.Sprintf("consensus has been stuck for %s at height %d round %d, step %s with %.0f%% prevotes and %.0f%% precommits",
+		c.StuckFor.Round(time.Second), c.Height, c.Round, c.Step, c.Prevotes, c.Precommits)
+}
+func (e *ConsensusStuckError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeConsensusStuck)
+}
+func newConsensusStuckError(consensus *ConsensusStats) *ConsensusStuckError {
+	return &ConsensusStuckError{consensus}
+}
+
+type MissedProposalsError struct {
+	missed  []MissedProposal
+	toCheck int64
+}
+
+func (e *MissedProposalsError) Error() string {
+	rounds := make([]string, len(e.missed))
+	for i, missed := range e.missed {
+		rounds[i] = fmt.Sprintf("%d/%d", missed.Height, missed.Round)
+	}
+	return fmt.Sprintf("missed %d proposals in the %d most recent blocks, at height/round %s", len(e.missed), e.toCheck, strings.Join(rounds, ", "))
+}
+func (e *MissedProposalsError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeMissedProposals)
+}
+func newMissedProposalsError(missed []MissedProposal, toCheck int64) *MissedProposalsError {
+	return &MissedProposalsError{missed, toCheck}
+}
+
+type SignatureLatencyError struct {
+	latency *SignatureLatencyStats
+}
+
+func (e *SignatureLatencyError) Error() string {
+	return fmt.Sprintf("signing latency degraded, median precommit %s behind the block time (p90 %s) while 90%% of the set signs within %s", e.latency.P50.Round(time.Millisecond), e.latency.P90.Round(time.Millisecond), e.latency.SetP90.Round(time.Millisecond))
+}
+func (e *SignatureLatencyError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeSignatureLatency)
+}
+func newSignatureLatencyError(latency *SignatureLatencyStats) *SignatureLatencyError {
+	return &SignatureLatencyError{latency}
+}
+
+type LowParticipationError struct {
+	participation float64
+	height        int64
+	threshold     float64
+}
+
+func (e *LowParticipationError) Error() string {
+	return fmt.Sprintf("network participation was %.1f%% of voting power at height %d, below %.1f%% and approaching the 2/3 needed to produce blocks", e.participation, e.height, e.threshold)
+}
+func (e *LowParticipationError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeLowParticipation)
+}
+func newLowParticipationError(participation float64, height int64, threshold float64) *LowParticipationError {
+	return &LowParticipationError{participation, height, threshold}
+}
+
+type ForkError struct {
+	height int64
+	forks  []string
+}
+
+func (e *ForkError) Error() string {
+	return fmt.Sprintf("block hashes diverge at height %d - %s", e.height, strings.Join(e.forks, " - "))
+}
+func (e *ForkError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeFork)
+}
+func newForkError(height int64, forks []string) *ForkError {
+	return &ForkError{height, forks}
+}
+
+type SentryConnectionsError struct {
+	sentries []string
+	all      bool
+}
+
+func (e *SentryConnectionsError) Error() string {
+	if e.all {
+		return fmt.Sprintf("validator is not connected to any of its sentries - %s", strings.Join(e.sentries, ", "))
+	}
+	return fmt.Sprintf("validator is not connected to sentries - %s", strings.Join(e.sentries, ", "))
+}
+func (e *SentryConnectionsError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeSentryConnections)
+}
+func (e *SentryConnectionsError) alertLevel() AlertLevel {
+	if e.all {
+		return alertLevelHigh
+	}
+	return alertLevelWarning
+}
+func newSentryConnectionsError(sentries []string, all bool) *SentryConnectionsError {
+	return &SentryConnectionsError{sentries, all}
+}
+
+type UnexpectedPeersError struct{ peers []string }
+
+func (e *UnexpectedPeersError) Error() string {
+	return fmt.Sprintf("validator is connected to peers that are not its sentries - %s", strings.Join(e.peers, ", "))
+}
+func (e *UnexpectedPeersError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeUnexpectedPeers)
+}
+func newUnexpectedPeersError(peers []string) *UnexpectedPeersError {
+	return &UnexpectedPeersError{peers}
+}
+
 type GenericRPCError struct{ msg string }
 
 func (e *GenericRPCError) Error() string { return e.msg }
@@ -151,6 +434,7 @@ func newSentryOutOfSyncError(sentry string, msg string) *SentryOutOfSyncError {
 
 type SentryHaltError struct {
 	sentry       string
+	height       int64
 	durationNano int64
 }
 
@@ -158,6 +442,88 @@ func (e *SentryHaltError) Error() string {
 	minutesHalted := int64(math.Round(float64(e.durationNano) / 6e10))
 	return fmt.Sprintf("%s has been halted for %dmin", e.sentry, minutesHalted)
 }
-func newSentryHaltError(sentry string, durationNano int64) *SentryHaltError {
-	return &SentryHaltError{sentry, durationNano}
+func newSentryHaltError(sentry string, height int64, durationNano int64) *SentryHaltError {
+	return &SentryHaltError{sentry, height, durationNano}
+}
+
+type SentryCatchingUpError struct {
+	sentry string
+	height int64
+}
+
+func (e *SentryCatchingUpError) Error() string {
+	return fmt.Sprintf("%s is catching up at height %d", e.sentry, e.height)
+}
+func newSentryCatchingUpError(sentry string, height int64) *SentryCatchingUpError {
+	return &SentryCatchingUpError{sentry, height}
+}
+
+type SentryLowPeersError struct {
+	sentry   string
+	peers    int
+	minPeers int
+}
+
+func (e *SentryLowPeersError) Error() string {
+	return fmt.Sprintf("%s has %d peers, below the minimum of %d", e.sentry, e.peers, e.minPeers)
+}
+func newSentryLowPeersError(sentry string, peers int, minPeers int) *SentryLowPeersError {
+	return &SentryLowPeersError{sentry, peers, minPeers}
+}
+
+type SentrySyncInfoError struct {
+	sentry string
+	msg    string
+}
+
+func (e *SentrySyncInfoError) Error() string {
+	return fmt.Sprintf("%s - unable to check catching up and peers - %s", e.sentry, e.msg)
+}
+func newSentrySyncInfoError(sentry string, msg string) *SentrySyncInfoError {
+	return &SentrySyncInfoError{sentry, msg}
+}
+
+type SentryVersionDriftError struct {
+	versions []string
+}
+
+func (e *SentryVersionDriftError) Error() string {
+	return fmt.Sprintf("sentries are running different versions - %s", strings.Join(e.versions, " - "))
+}
+func (e *SentryVersionDriftError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeSentryVersionDrift)
+}
+func newSentryVersionDriftError(versions []string) *SentryVersionDriftError {
+	return &SentryVersionDriftError{versions}
+}
+
+type SentryMinVersionError struct {
+	sentries   []string
+	minVersion string
+}
+
+func (e *SentryMinVersionError) Error() string {
+	return fmt.Sprintf("sentries running below minimum version %s: %s", e.minVersion, strings.Join(e.sentries, ", "))
+}
+func (e *SentryMinVersionError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeSentryMinVersion)
+}
+func newSentryMinVersionError(sentries []string, minVersion string) *SentryMinVersionError {
+	return &SentryMinVersionError{sentries, minVersion}
+}
+
+type SentryVersionChangedError struct {
+	sentry          string
+	previousVersion string
+	version         string
+}
+
+func (e *SentryVersionChangedError) Error() string {
+	return fmt.Sprintf("%s - version changed from %s to %s", e.sentry, e.previousVersion, e.version)
+}
+func (e *SentryVersionChangedError) Active(config AlertConfig) bool {
+	return config.AlertActive(alertTypeSentryVersionChanged)
+}
+func newSentryVersionChangedError(sentry string, previousVersion string, version string) *SentryVersionChangedError {
+	return &SentryVersionChangedError{sentry, previousVersion, version}
 }
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/bytes"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	evidenceRetention = 24 * time.Hour // how long reported evidence is remembered, so each piece of evidence is only alerted once
+)
+
+// evidence of double signing by a monitored validator, found in a committed block
+type doubleSignEvidence struct {
+	Validator string
+	ChainID   string
+	Hash      string
+	Height    int64 // height of the block the evidence was committed in
+	Details   string
+	Found     time.Time
+}
+
+func getVoteTypeName(voteType tmproto.SignedMsgType) string {
+	switch voteType {
+	case tmproto.PrevoteType:
+		return "prevote"
+	case tmproto.PrecommitType:
+		return "precommit"
+	default:
+		return voteType.String()
+	}
+}
+
+func getVoteBlockName(vote *types.Vote) string {
+	if vote.BlockID.IsZero() {
+		return "nil"
+	}
+	return vote.BlockID.Hash.String()
+}
+
+// evidence in the block against the validator with the consensus address
+func getDoubleSignEvidence(vm *ValidatorMonitor, block *blockRecord, hexAddress []byte) (evidence []*doubleSignEvidence) {
+	for _, ev := range block.Evidence {
+		var details string
+		switch ev := ev.(type) {
+		case *types.DuplicateVoteEvidence:
+			if ev.VoteA == nil || ev.VoteB == nil || !reflect.DeepEqual(ev.VoteA.ValidatorAddress, bytes.HexBytes(hexAddress)) {
+				continue
+			}
+			details = fmt.Sprintf("duplicate %s at height %d round %d for blocks %s and %s",
+				getVoteTypeName(ev.VoteA.Type), ev.VoteA.Height, ev.VoteA.Round, getVoteBlockName(ev.VoteA), getVoteBlockName(ev.VoteB))
+		case *types.LightClientAttackEvidence:
+			found := false
+			for _, validator := range ev.ByzantineValidators {
+				if reflect.DeepEqual(validator.Address, bytes.HexBytes(hexAddress)) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+			details = fmt.Sprintf("light client attack at height %d with common height %d", ev.Height(), ev.CommonHeight)
+		default:
+			continue
+		}
+		evidence = append(evidence, &doubleSignEvidence{
+			Validator: vm.Name,
+			ChainID:   vm.ChainID,
+			Hash:      fmt.Sprintf("%X", ev.Hash()),
+			Height:    block.Height,
+			Details:   details,
+			Found:     time.Now(),
+		})
+	}
+	return
+}
+
+func doubleSignAlertKey(hash string) string {
+	if len(hash) > 16 {
+		hash = hash[:16]
+	}
+	return fmt.Sprintf("doubleSign-%s", hash)
+}
+
+// signerEvidenceRegistry shares double sign evidence between the monitors of validators that use the same signer infrastructure
+type signerEvidenceRegistry struct {
+	mutex    sync.Mutex
+	evidence []signerEvidenceEntry
+}
+
+type signerEvidenceEntry struct {
+	evidence *doubleSignEvidence
+	labels   []string // signer labels of the validator the evidence is against
+}
+
+var signerEvidence = &signerEvidenceRegistry{}
+
+func (r *signerEvidenceRegistry) report(vm *ValidatorMonitor, evidence *doubleSignEvidence) {
+	if len(vm.SignerLabels) == 0 {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, entry := range r.evidence {
+		if entry.evidence.Hash == evidence.Hash {
+			return
+		}
+	}
+	r.evidence = append(r.evidence, signerEvidenceEntry{evidence: evidence, labels: vm.SignerLabels})
+}
+
+// evidence against other validators that share a signer label with the validator
+func (r *signerEvidenceRegistry) errorsFor(vm *ValidatorMonitor) (errs []IgnorableError) {
+	if len(vm.SignerLabels) == 0 {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	kept := r.evidence[:0]
+	for _, entry := range r.evidence {
+		if time.Since(entry.evidence.Found) > evidenceRetention {
+			continue
+		}
+		kept = append(kept, entry)
+		if entry.evidence.Validator == vm.Name {
+			continue
+		}
+		if labels := sharedLabels(vm.SignerLabels, entry.labels); len(labels) > 0 {
+			errs = append(errs, newSignerDoubleSignError(entry.evidence, labels))
+		}
+	}
+	r.evidence = kept
+	return
+}
+
+func sharedLabels(a []string, b []string) (shared []string) {
+	for _, label := range a {
+		if containsString(b, label) {
+			shared = append(shared, label)
+		}
+	}
+	return
+}
+
+// requires locked alertState. Returns true if the evidence has not been alerted yet.
+func (a *ValidatorAlertState) markEvidenceReported(hash string) bool {
+	for reportedHash, reported := range a.ReportedEvidence {
+		if time.Since(time.Unix(reported, 0)) > evidenceRetention {
+			delete(a.ReportedEvidence, reportedHash)
+			delete(a.AlertKeyLevels, doubleSignAlertKey(reportedHash))
+		}
+	}
+	if _, ok := a.ReportedEvidence[hash]; ok {
+		return false
+	}
+	a.ReportedEvidence[hash] = time.Now().Unix()
+	return true
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	forkCheckRPCName = "RPC"
+)
+
+// block hashes of the rpc node and sentries at a common height
+type BlockHashStats struct {
+	Height   int64
+	Hashes   map[string]string // block hash by node name, for nodes that returned the block
+	Diverged bool
+}
+
+type lcdBlockResponse struct {
+	BlockID struct {
+		Hash string `json:"hash"`
+	} `json:"block_id"`
+}
+
+// highest height that the rpc node and every sentry with a known height have reached
+func getForkCheckHeight(stats *ValidatorStats) int64 {
+	height := stats.Height
+	for _, sentryStats := range stats.SentryStats {
+		if sentryStats.Height > 0 && sentryStats.Height < height {
+			height = sentryStats.Height
+		}
+	}
+	return height
+}
+
+func getRPCBlockHash(rpc Endpoint, height int64) (string, error) {
+	client, err := newClient(rpc)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+	defer cancel()
+	block, err := client.Block(ctx, &height)
+	if err != nil {
+		return "", err
+	}
+	return block.BlockID.Hash.String(), nil
+}
+
+// block hash at height from the sentry's grpc, then rpc, then lcd
+func getSentryBlockHash(sentry Sentry, height int64) (string, error) {
+	switch {
+	case sentry.GRPC.URL != "":
+		block, err := getSentryBlockByHeight(sentry.GRPC, height)
+		if err != nil {
+			return "", err
+		}
+		if block.BlockId == nil {
+			return "", fmt.Errorf("no block id for height %d", height)
+		}
+		return fmt.Sprintf("%X", block.BlockId.Hash), nil
+	case sentry.RPC.URL != "":
+		return getRPCBlockHash(sentry.RPC, height)
+	default:
+		var block lcdBlockResponse
+		if err := getLCD(sentry.LCD, fmt.Sprintf("/cosmos/base/tendermint/v1beta1/blocks/%d", height), &block); err != nil {
+			return "", err
+		}
+		hash, err := base64.StdEncoding.DecodeString(block.BlockID.Hash)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%X", hash), nil
+	}
+}
+
+// Compares the block hash at a common height between the rpc node and the sentries, nodes that cannot return the block are skipped.
+// Requires the latest heights of the rpc node and the sentries in stats.
+func getForkErrors(vm *ValidatorMonitor, rpc Endpoint, stats *ValidatorStats, alertConfig AlertConfig) (errs []error) {
+	if vm.Sentries == nil || stats.Height == 0 {
+		return
+	}
+	height := getForkCheckHeight(stats)
+	blockHashes := &BlockHashStats{Height: height, Hashes: make(map[string]string)}
+	var hashesLock sync.Mutex
+	addHash := func(name string, hash string, err error) {
+		if err != nil {
+			fmt.Printf("Error fetching block hash at height %d from %s: %v\n", height, name, err)
+			return
+		}
+		hashesLock.Lock()
+		blockHashes.Hashes[name] = hash
+		hashesLock.Unlock()
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		hash, err := getRPCBlockHash(rpc, height)
+		addHash(forkCheckRPCName, hash, err)
+	}()
+	for _, sentry := range *vm.Sentries {
+		wg.Add(1)
+		go func(sentry Sentry) {
+			defer wg.Done()
+			hash, err := getSentryBlockHash(sentry, height)
+			addHash(sentry.Name, hash, err)
+		}(sentry)
+	}
+	wg.Wait()
+	stats.BlockHashes = blockHashes
+
+	nodesByHash := make(map[string][]string)
+	for name, hash := range blockHashes.Hashes {
+		nodesByHash[hash] = append(nodesByHash[hash], name)
+	}
+	if len(nodesByHash) < 2 {
+		return
+	}
+	blockHashes.Diverged = true
+	var forks []string
+	for hash, nodes := range nodesByHash {
+		sort.Strings(nodes)
+		forks = append(forks, fmt.Sprintf("%s: %s", strings.Join(nodes, ", "), shortHash(hash)))
+	}
+	sort.Strings(forks)
+	if err := newForkError(height, forks); err.Active(alertConfig) {
+		stats.increaseAlertLevel(alertLevelCritical)
+		errs = append(errs, err)
+	}
+	return
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	cosmosClient "github.com/cosmos/cosmos-sdk/client"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	paramsproposal "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+)
+
+const (
+	// alerts for proposals without a vote escalate as the end of the voting period approaches
+	govVoteWarningThreshold  = 72 * time.Hour
+	govVoteHighThreshold     = 24 * time.Hour
+	govVoteCriticalThreshold = 6 * time.Hour
+
+	operatorAddressSuffix = "valoper"
+)
+
+// proposal content types of the sdk modules, used to get proposal titles
+var proposalInterfaceRegistry = newProposalInterfaceRegistry()
+
+func newProposalInterfaceRegistry() codectypes.InterfaceRegistry {
+	registry := codectypes.NewInterfaceRegistry()
+	govtypes.RegisterInterfaces(registry)
+	distrtypes.RegisterInterfaces(registry)
+	paramsproposal.RegisterInterfaces(registry)
+	upgradetypes.RegisterInterfaces(registry)
+	return registry
+}
+
+type GovProposalStats struct {
+	ID            uint64
+	Title         string
+	VotingEndTime time.Time
+	Voted         bool
+}
+
+func govVoteAlertKey(proposalID uint64) string {
+	return fmt.Sprintf("%s-%d", alertTypeGovVote, proposalID)
+}
+
+func (stats ValidatorStats) hasVotedOnProposal(proposalID uint64) bool {
+	for _, proposal := range stats.GovProposals {
+		if proposal.ID == proposalID {
+			return proposal.Voted
+		}
+	}
+	return false
+}
+
+// validators vote with the account address of their operator address
+func getGovVoterAddress(operatorAddress string) (string, error) {
+	hrp, bz, err := bech32.DecodeAndConvert(operatorAddress)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(hrp, operatorAddressSuffix) {
+		return "", fmt.Errorf("operator address %s does not have a %s prefix", operatorAddress, operatorAddressSuffix)
+	}
+	return bech32.ConvertAndEncode(strings.TrimSuffix(hrp, operatorAddressSuffix), bz)
+}
+
+// The proposal content is unpacked with the proposal types of the sdk modules, since chains can have their own proposal types
+// the type is used as the title of any other proposals.
+func getProposalTitle(proposal govtypes.Proposal) string {
+	if proposal.Content == nil {
+		return ""
+	}
+	if err := proposal.UnpackInterfaces(proposalInterfaceRegistry); err != nil || proposal.GetContent() == nil {
+		return proposal.Content.TypeUrl
+	}
+	return proposal.GetContent().GetTitle()
+}
+
+func getGovVoteAlertLevel(votingEndTime time.Time) AlertLevel {
+	switch remaining := time.Until(votingEndTime); {
+	case remaining <= govVoteCriticalThreshold:
+		return alertLevelCritical
+	case remaining <= govVoteHighThreshold:
+		return alertLevelHigh
+	case remaining <= govVoteWarningThreshold:
+		return alertLevelWarning
+	default:
+		return alertLevelNone
+	}
+}
+
+func formatDurationHours(d time.Duration) string {
+	hours := int64(math.Round(d.Hours()))
+	if hours < 1 {
+		return fmt.Sprintf("%dmin", int64(math.Round(d.Minutes())))
+	}
+	return fmt.Sprintf("%dh", hours)
+}
+
+// checks whether the validator has voted on proposals in their voting period
+func monitorGovernance(client *cosmosClient.Context, vm *ValidatorMonitor, stats *ValidatorStats) (errs []IgnorableError) {
+	stats.GovChecked = false
+	stats.GovProposals = nil
+	voter, err := getGovVoterAddress(vm.OperatorAddress)
+	if err != nil {
+		errs = append(errs, newIgnorableError(err))
+		return
+	}
+	proposals, err := getVotingProposals(client)
+	if err != nil {
+		errs = append(errs, newGenericRPCError(err.Error()))
+		return
+	}
+	var proposalStats []*GovProposalStats
+	for _, proposal := range proposals {
+		voted, err := getHasVoted(client, proposal.ProposalId, voter)
+		if err != nil {
+			errs = append(errs, newGenericRPCError(err.Error()))
+			return
+		}
+		title := getProposalTitle(proposal)
+		proposalStats = append(proposalStats, &GovProposalStats{
+			ID:            proposal.ProposalId,
+			Title:         title,
+			VotingEndTime: proposal.VotingEndTime,
+			Voted:         voted,
+		})
+		if !voted && getGovVoteAlertLevel(proposal.VotingEndTime) > alertLevelNone {
+			errs = append(errs, newGovVoteError(proposal.ProposalId, title, proposal.VotingEndTime))
+		}
+	}
+	stats.GovChecked = true
+	stats.GovProposals = proposalStats
+	return
+}
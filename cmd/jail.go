@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"time"
+
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+)
+
+const (
+	// alerts escalate as the fraction of the missed blocks allowed in the slashing window that remain shrinks
+	jailMarginWarningThreshold  = 0.5
+	jailMarginHighThreshold     = 0.2
+	jailMarginCriticalThreshold = 0.05
+)
+
+// sets the number of blocks that can still be missed in the slashing window before the validator is jailed.
+// Validators are jailed when the missed blocks counter exceeds the window minus the minimum signed blocks.
+func (stats *ValidatorStats) setJailMargin(params slashingtypes.Params, signingInfo slashingtypes.ValidatorSigningInfo) {
+	minSigned := params.MinSignedPerWindow.MulInt64(params.SignedBlocksWindow).RoundInt64()
+	stats.JailMarginMaxMissed = params.SignedBlocksWindow - minSigned
+	stats.JailMarginBlocks = stats.JailMarginMaxMissed - signingInfo.MissedBlocksCounter
+	if stats.JailMarginBlocks < 0 {
+		stats.JailMarginBlocks = 0
+	}
+	stats.JailMarginChecked = true
+}
+
+// estimated time until jailing if every block is missed, zero if the block time is unknown
+func (stats ValidatorStats) jailMarginDuration() time.Duration {
+	return stats.AverageBlockTime * time.Duration(stats.JailMarginBlocks+1)
+}
+
+func getJailMarginAlertLevel(marginBlocks int64, maxMissed int64) AlertLevel {
+	if maxMissed <= 0 {
+		return alertLevelNone
+	}
+	switch remaining := float64(marginBlocks) / float64(maxMissed); {
+	case remaining <= jailMarginCriticalThreshold:
+		return alertLevelCritical
+	case remaining <= jailMarginHighThreshold:
+		return alertLevelHigh
+	case remaining <= jailMarginWarningThreshold:
+		return alertLevelWarning
+	default:
+		return alertLevelNone
+	}
+}
+
+// average time between the blocks, zero if it cannot be determined
+func getAverageBlockTime(newest *blockRecord, oldest *blockRecord) time.Duration {
+	if newest == nil || oldest == nil || newest.Height <= oldest.Height {
+		return 0
+	}
+	return newest.Time.Sub(oldest.Time) / time.Duration(newest.Height-oldest.Height)
+}
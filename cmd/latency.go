@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	defaultSignatureLatencyThreshold int64 = 500 // milliseconds behind the block time before slow signing is alerted
+	minSignatureLatencyBlocks              = 10  // signed blocks needed in the recent blocks checked before latency is alerted
+)
+
+// How far behind the block time the precommits in the recent blocks checked were signed.
+// The block time is the voting power weighted median of the precommit timestamps, so latency can be negative for early signers.
+type SignatureLatencyStats struct {
+	Blocks int // signed blocks the validator latency is from
+	P50    time.Duration
+	P90    time.Duration
+	P99    time.Duration
+	SetP50 time.Duration // latency of the other validators' precommits in the same blocks
+	SetP90 time.Duration
+}
+
+type signatureLatencies struct {
+	validator []time.Duration
+	set       []time.Duration
+}
+
+func (vm *ValidatorMonitor) signatureLatencyThreshold() time.Duration {
+	if vm.SignatureLatencyThreshold == nil {
+		return time.Duration(defaultSignatureLatencyThreshold) * time.Millisecond
+	}
+	return time.Duration(*vm.SignatureLatencyThreshold) * time.Millisecond
+}
+
+// adds the latency of each precommit for the block in the last commit
+func (l *signatureLatencies) add(block *blockRecord, hexAddress []byte) {
+	for _, voter := range block.LastCommit.Signatures {
+		if voter.BlockIDFlag != types.BlockIDFlagCommit {
+			continue
+		}
+		latency := voter.Timestamp.Sub(block.Time)
+		if reflect.DeepEqual(voter.ValidatorAddress, bytes.HexBytes(hexAddress)) {
+			l.validator = append(l.validator, latency)
+		} else {
+			l.set = append(l.set, latency)
+		}
+	}
+}
+
+// nil until the validator has signed a block checked
+func (l *signatureLatencies) stats() *SignatureLatencyStats {
+	if len(l.validator) == 0 {
+		return nil
+	}
+	sort.Slice(l.validator, func(i, j int) bool { return l.validator[i] < l.validator[j] })
+	sort.Slice(l.set, func(i, j int) bool { return l.set[i] < l.set[j] })
+	return &SignatureLatencyStats{
+		Blocks: len(l.validator),
+		P50:    getPercentile(l.validator, 50),
+		P90:    getPercentile(l.validator, 90),
+		P99:    getPercentile(l.validator, 99),
+		SetP50: getPercentile(l.set, 50),
+		SetP90: getPercentile(l.set, 90),
+	}
+}
+
+// nearest rank percentile of sorted latencies
+func getPercentile(sorted []time.Duration, percentile float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Latency is degraded when the validator's median precommit is slower than 90% of the other precommits,
+// and further behind the block time than the threshold so sub-second differences on fast chains are not alerted.
+func (s *SignatureLatencyStats) degraded(threshold time.Duration) bool {
+	return s.Blocks >= minSignatureLatencyBlocks && s.P50 > s.SetP90 && s.P50 > threshold
+}
@@ -0,0 +1,451 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	metricsNamespace = "halflife"
+)
+
+var (
+	validatorLabels = []string{"validator", "chain_id"}
+	sentryLabels    = []string{"validator", "chain_id", "sentry"}
+
+	validatorHeightGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_height",
+		Help:      "Latest block height of the validator's RPC node.",
+	}, validatorLabels)
+	validatorLatestBlockTimestampGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_latest_block_timestamp_seconds",
+		Help:      "Timestamp of the latest block of the validator's RPC node.",
+	}, validatorLabels)
+	validatorRecentMissedBlocksGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_recent_missed_blocks",
+		Help:      "Number of the most recent blocks checked that the validator did not sign.",
+	}, validatorLabels)
+	validatorRecentNilVotesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_recent_nil_votes",
+		Help:      "Number of the most recent blocks checked that the validator voted nil for.",
+	}, validatorLabels)
+	validatorRecentBlocksCheckedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_recent_blocks_checked",
+		Help:      "Number of the most recent blocks that are checked for the validator's signature.",
+	}, validatorLabels)
+	validatorSlashingPeriodUptimeGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_slashing_period_uptime_percent",
+		Help:      "Percentage of blocks signed by the validator in the slashing window.",
+	}, validatorLabels)
+	validatorLastSignedHeightGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_last_signed_height",
+		Help:      "Height of the latest block signed by the validator, -1 if unknown.",
+	}, validatorLabels)
+	validatorLastSignedTimestampGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_last_signed_timestamp_seconds",
+		Help:      "Timestamp of the latest block signed by the validator.",
+	}, validatorLabels)
+	validatorJailMarginBlocksGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_jail_margin_blocks",
+		Help:      "Number of blocks that can still be missed in the slashing window before the validator is jailed.",
+	}, validatorLabels)
+	validatorActiveGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_active",
+		Help:      "1 if the validator is in the active set, otherwise 0.",
+	}, validatorLabels)
+	validatorVotingPowerGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_voting_power",
+		Help:      "Voting power of the validator, 0 if not in the active set.",
+	}, validatorLabels)
+	validatorActiveSetRankGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_active_set_rank",
+		Help:      "Position of the validator in the active set by voting power, starting at 1, 0 if not in the active set.",
+	}, validatorLabels)
+	validatorGovProposalsNotVotedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_gov_proposals_not_voted",
+		Help:      "Number of proposals in their voting period that the validator has not voted on.",
+	}, validatorLabels)
+	validatorUpgradeHeightGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_upgrade_height",
+		Help:      "Height of the planned software upgrade, 0 if no upgrade is planned.",
+	}, validatorLabels)
+	validatorSentriesConnectedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_sentries_connected",
+		Help:      "Number of sentries the validator node is connected to, only when the node rpc is configured.",
+	}, validatorLabels)
+	validatorUnexpectedPeersGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_unexpected_peers",
+		Help:      "Number of peers of the validator node that are not its sentries or allowed peers, only when the node rpc is configured.",
+	}, validatorLabels)
+	validatorBlockHashDivergedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_block_hash_diverged",
+		Help:      "1 if the block hashes of the rpc node and sentries diverged at the latest common height, otherwise 0.",
+	}, validatorLabels)
+	validatorNetworkParticipationGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_network_participation",
+		Help:      "Lowest percentage of voting power that signed one of the recent blocks checked.",
+	}, validatorLabels)
+	validatorSignatureLatencyP50Gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_signature_latency_p50_seconds",
+		Help:      "Median time the validator's precommits were signed behind the block time in the recent blocks checked.",
+	}, validatorLabels)
+	validatorSignatureLatencyP90Gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_signature_latency_p90_seconds",
+		Help:      "90th percentile time the validator's precommits were signed behind the block time in the recent blocks checked.",
+	}, validatorLabels)
+	validatorSetSignatureLatencyP90Gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_set_signature_latency_p90_seconds",
+		Help:      "90th percentile time the other validators' precommits were signed behind the block time in the same blocks.",
+	}, validatorLabels)
+	validatorProposedBlocksGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_proposed_blocks",
+		Help:      "Number of the recent blocks checked that were proposed by the validator.",
+	}, validatorLabels)
+	validatorExpectedProposalsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_expected_proposals",
+		Help:      "Number of the recent blocks checked the validator was expected to propose by its share of voting power.",
+	}, validatorLabels)
+	validatorMissedProposalsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_missed_proposals",
+		Help:      "Number of rounds in the recent blocks checked the validator was the proposer of but a later round was committed.",
+	}, validatorLabels)
+	validatorConsensusRoundGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_consensus_round",
+		Help:      "Consensus round of the current height.",
+	}, validatorLabels)
+	validatorAlertLevelGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_alert_level",
+		Help:      "Current alert level of the validator: 0 none, 1 warning, 2 high, 3 critical.",
+	}, validatorLabels)
+	validatorRPCErrorGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "validator_rpc_error",
+		Help:      "1 if the validator's RPC node had errors in the latest check, otherwise 0.",
+	}, validatorLabels)
+
+	sentryHeightGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "sentry_height",
+		Help:      "Latest block height of the sentry.",
+	}, sentryLabels)
+	sentryHealthyGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "sentry_healthy",
+		Help:      "1 if the sentry had no errors in the latest check, otherwise 0.",
+	}, sentryLabels)
+	sentryPeersGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "sentry_peers",
+		Help:      "Number of peers of the sentry, only for sentries with an rpc endpoint.",
+	}, sentryLabels)
+	sentryVersionGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "sentry_version_info",
+		Help:      "Application version of the sentry, always 1.",
+	}, append(sentryLabels, "version"))
+
+	notificationsSentCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "notifications_sent_total",
+		Help:      "Number of alert notifications sent successfully, by notification sink and kind (alert or cleared).",
+	}, []string{"validator", "notification", "kind"})
+	doubleSignEvidenceCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "double_sign_evidence_total",
+		Help:      "Number of pieces of double sign evidence committed against the validator.",
+	}, validatorLabels)
+	rpcErrorsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "rpc_errors_total",
+		Help:      "Number of RPC and gRPC errors, by error type.",
+	}, append(validatorLabels, "type"))
+)
+
+// version series are replaced when the version changes, so the previous version of each sentry is kept to remove it
+var (
+	sentryVersions     = make(map[string]string)
+	sentryVersionsLock sync.Mutex
+)
+
+func init() {
+	prometheus.MustRegister(
+		validatorHeightGauge,
+		validatorLatestBlockTimestampGauge,
+		validatorRecentMissedBlocksGauge,
+		validatorRecentNilVotesGauge,
+		validatorRecentBlocksCheckedGauge,
+		validatorSlashingPeriodUptimeGauge,
+		validatorLastSignedHeightGauge,
+		validatorLastSignedTimestampGauge,
+		validatorJailMarginBlocksGauge,
+		validatorActiveGauge,
+		validatorVotingPowerGauge,
+		validatorActiveSetRankGauge,
+		validatorGovProposalsNotVotedGauge,
+		validatorUpgradeHeightGauge,
+		validatorSentriesConnectedGauge,
+		validatorUnexpectedPeersGauge,
+		validatorBlockHashDivergedGauge,
+		validatorNetworkParticipationGauge,
+		validatorSignatureLatencyP50Gauge,
+		validatorSignatureLatencyP90Gauge,
+		validatorSetSignatureLatencyP90Gauge,
+		validatorProposedBlocksGauge,
+		validatorExpectedProposalsGauge,
+		validatorMissedProposalsGauge,
+		validatorConsensusRoundGauge,
+		validatorAlertLevelGauge,
+		validatorRPCErrorGauge,
+		sentryHeightGauge,
+		sentryHealthyGauge,
+		sentryPeersGauge,
+		sentryVersionGauge,
+		notificationsSentCounter,
+		doubleSignEvidenceCounter,
+		rpcErrorsCounter,
+	)
+}
+
+// serves /metrics on the address in the background
+func serveMetrics(address string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		fmt.Printf("Serving metrics on %s/metrics\n", address)
+		if err := http.ListenAndServe(address, mux); err != nil {
+			fmt.Printf("Error serving metrics: %v\n", err)
+		}
+	}()
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func recordValidatorMetrics(vm *ValidatorMonitor, stats ValidatorStats) {
+	labels := prometheus.Labels{"validator": vm.Name, "chain_id": vm.ChainID}
+	if !stats.Timestamp.IsZero() {
+		validatorHeightGauge.With(labels).Set(float64(stats.Height))
+		validatorLatestBlockTimestampGauge.With(labels).Set(float64(stats.Timestamp.Unix()))
+	}
+	if !vm.FullNode && stats.ActiveSetChecked {
+		validatorActiveGauge.With(labels).Set(boolToFloat(!stats.Inactive))
+		validatorVotingPowerGauge.With(labels).Set(float64(stats.VotingPower))
+		validatorActiveSetRankGauge.With(labels).Set(float64(stats.ActiveSetRank))
+	}
+	if stats.JailMarginChecked {
+		validatorJailMarginBlocksGauge.With(labels).Set(float64(stats.JailMarginBlocks))
+	}
+	if stats.UpgradeChecked {
+		var upgradeHeight int64
+		if stats.UpgradePlan != nil {
+			upgradeHeight = stats.UpgradePlan.Height
+		}
+		validatorUpgradeHeightGauge.With(labels).Set(float64(upgradeHeight))
+	}
+	if stats.GovChecked {
+		notVoted := 0
+		for _, proposal := range stats.GovProposals {
+			if !proposal.Voted {
+				notVoted++
+			}
+		}
+		validatorGovProposalsNotVotedGauge.With(labels).Set(float64(notVoted))
+	}
+	if !stats.fullNode(vm) {
+		validatorRecentMissedBlocksGauge.With(labels).Set(float64(stats.RecentMissedBlocks))
+		validatorRecentNilVotesGauge.With(labels).Set(float64(stats.RecentNilVotes))
+		validatorRecentBlocksCheckedGauge.With(labels).Set(float64(vm.RecentBlocksToCheck))
+		validatorLastSignedHeightGauge.With(labels).Set(float64(stats.LastSignedBlockHeight))
+		if stats.SlashingPeriodUptime > 0 {
+			validatorSlashingPeriodUptimeGauge.With(labels).Set(stats.SlashingPeriodUptime)
+		}
+		if !stats.LastSignedBlockTimestamp.IsZero() {
+			validatorLastSignedTimestampGauge.With(labels).Set(float64(stats.LastSignedBlockTimestamp.Unix()))
+		}
+		if stats.ParticipationChecked {
+			validatorNetworkParticipationGauge.With(labels).Set(stats.MinParticipation)
+		}
+		if stats.SignatureLatency != nil {
+			validatorSignatureLatencyP50Gauge.With(labels).Set(stats.SignatureLatency.P50.Seconds())
+			validatorSignatureLatencyP90Gauge.With(labels).Set(stats.SignatureLatency.P90.Seconds())
+			validatorSetSignatureLatencyP90Gauge.With(labels).Set(stats.SignatureLatency.SetP90.Seconds())
+		}
+		if stats.Proposals != nil {
+			validatorProposedBlocksGauge.With(labels).Set(float64(stats.Proposals.Proposed))
+			validatorExpectedProposalsGauge.With(labels).Set(stats.Proposals.Expected)
+			validatorMissedProposalsGauge.With(labels).Set(float64(len(stats.Proposals.Missed)))
+		}
+	}
+	if stats.Topology != nil {
+		validatorSentriesConnectedGauge.With(labels).Set(float64(len(stats.Topology.ConnectedSentries)))
+		validatorUnexpectedPeersGauge.With(labels).Set(float64(len(stats.Topology.UnexpectedPeers)))
+	}
+	if stats.BlockHashes != nil {
+		validatorBlockHashDivergedGauge.With(labels).Set(boolToFloat(stats.BlockHashes.Diverged))
+	}
+	if stats.Consensus != nil {
+		validatorConsensusRoundGauge.With(labels).Set(float64(stats.Consensus.Round))
+	}
+	validatorAlertLevelGauge.With(labels).Set(float64(stats.AlertLevel))
+	validatorRPCErrorGauge.With(labels).Set(boolToFloat(stats.RPCError))
+
+	sentryVersionsLock.Lock()
+	defer sentryVersionsLock.Unlock()
+	for _, sentryStats := range stats.SentryStats {
+		sentryLabels := prometheus.Labels{"validator": vm.Name, "chain_id": vm.ChainID, "sentry": sentryStats.Name}
+		sentryHealthyGauge.With(sentryLabels).Set(boolToFloat(sentryStats.SentryAlertType == sentryAlertTypeNone))
+		if sentryStats.PeersChecked {
+			sentryPeersGauge.With(sentryLabels).Set(float64(sentryStats.Peers))
+		}
+		if sentryStats.Height == 0 {
+			continue
+		}
+		sentryHeightGauge.With(sentryLabels).Set(float64(sentryStats.Height))
+		versionKey := fmt.Sprintf("%s/%s", vm.Name, sentryStats.Name)
+		if previousVersion, ok := sentryVersions[versionKey]; ok && previousVersion != sentryStats.Version {
+			sentryVersionGauge.DeleteLabelValues(vm.Name, vm.ChainID, sentryStats.Name, previousVersion)
+		}
+		sentryVersions[versionKey] = sentryStats.Version
+		sentryVersionGauge.WithLabelValues(vm.Name, vm.ChainID, sentryStats.Name, sentryStats.Version).Set(1)
+	}
+}
+
+// counts errors found in a check that are RPC or gRPC errors, including errors for ignored alerts
+func recordRPCErrorMetrics(vm *ValidatorMonitor, err error) {
+	var errorType string
+	switch err.(type) {
+	case *GenericRPCError:
+		errorType = "generic_rpc"
+	case *OutOfSyncError:
+		errorType = "out_of_sync"
+	case *BlockFetchError:
+		errorType = "block_fetch"
+	case *SentryGRPCError:
+		errorType = "sentry_grpc"
+	default:
+		return
+	}
+	rpcErrorsCounter.WithLabelValues(vm.Name, vm.ChainID, errorType).Inc()
+}
+
+func recordDoubleSignMetrics(vm *ValidatorMonitor) {
+	doubleSignEvidenceCounter.WithLabelValues(vm.Name, vm.ChainID).Inc()
+}
+
+func recordNotificationSent(validator string, notification string, kind string) {
+	notificationsSentCounter.WithLabelValues(validator, notification, kind).Inc()
+}
+
+// removes the series of a validator that is no longer monitored
+func removeValidatorMetrics(vm *ValidatorMonitor) {
+	for _, gauge := range []*prometheus.GaugeVec{
+		validatorHeightGauge,
+		validatorLatestBlockTimestampGauge,
+		validatorRecentMissedBlocksGauge,
+		validatorRecentNilVotesGauge,
+		validatorRecentBlocksCheckedGauge,
+		validatorSlashingPeriodUptimeGauge,
+		validatorLastSignedHeightGauge,
+		validatorLastSignedTimestampGauge,
+		validatorJailMarginBlocksGauge,
+		validatorActiveGauge,
+		validatorVotingPowerGauge,
+		validatorActiveSetRankGauge,
+		validatorGovProposalsNotVotedGauge,
+		validatorUpgradeHeightGauge,
+		validatorSentriesConnectedGauge,
+		validatorUnexpectedPeersGauge,
+		validatorBlockHashDivergedGauge,
+		validatorNetworkParticipationGauge,
+		validatorSignatureLatencyP50Gauge,
+		validatorSignatureLatencyP90Gauge,
+		validatorSetSignatureLatencyP90Gauge,
+		validatorProposedBlocksGauge,
+		validatorExpectedProposalsGauge,
+		validatorMissedProposalsGauge,
+		validatorConsensusRoundGauge,
+		validatorAlertLevelGauge,
+		validatorRPCErrorGauge,
+	} {
+		gauge.DeleteLabelValues(vm.Name, vm.ChainID)
+	}
+	if vm.Sentries == nil {
+		return
+	}
+	for _, sentry := range *vm.Sentries {
+		removeSentryMetrics(vm, sentry.Name)
+	}
+}
+
+// removes the series of a sentry that is no longer monitored
+func removeSentryMetrics(vm *ValidatorMonitor, sentryName string) {
+	sentryVersionsLock.Lock()
+	defer sentryVersionsLock.Unlock()
+	sentryHeightGauge.DeleteLabelValues(vm.Name, vm.ChainID, sentryName)
+	sentryHealthyGauge.DeleteLabelValues(vm.Name, vm.ChainID, sentryName)
+	sentryPeersGauge.DeleteLabelValues(vm.Name, vm.ChainID, sentryName)
+	versionKey := fmt.Sprintf("%s/%s", vm.Name, sentryName)
+	if version, ok := sentryVersions[versionKey]; ok {
+		sentryVersionGauge.DeleteLabelValues(vm.Name, vm.ChainID, sentryName, version)
+		delete(sentryVersions, versionKey)
+	}
+}
+
+// removes the series of a validator's config that are not in its reloaded config
+func removeChangedValidatorMetrics(previous *ValidatorMonitor, vm *ValidatorMonitor) {
+	if previous.ChainID != vm.ChainID {
+		removeValidatorMetrics(previous)
+		return
+	}
+	if previous.Sentries == nil {
+		return
+	}
+	for _, previousSentry := range *previous.Sentries {
+		found := false
+		if vm.Sentries != nil {
+			for _, sentry := range *vm.Sentries {
+				if sentry.Name == previousSentry.Name {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			removeSentryMetrics(previous, previousSentry.Name)
+		}
+	}
+}
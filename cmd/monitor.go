@@ -3,70 +3,142 @@ package cmd
 import (
 	"fmt"
 	"log"
-	"os"
 	"sync"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
 )
 
 var monitorCmd = &cobra.Command{
 	Use:   "monitor",
 	Short: "Daemon to monitor validators",
-	Long:  "Monitors validators and pushes alerts to Discord using the configuration in config.yaml",
+	Long: `Monitors validators and pushes alerts to the notification services (Discord, Slack, PagerDuty) in the configuration in config.yaml
+
+config.yaml is reloaded when it changes or when SIGHUP is received`,
 	Run: func(cmd *cobra.Command, args []string) {
 		configFile, _ := cmd.Flags().GetString("file")
-		dat, err := os.ReadFile(configFile)
+		config, err := loadConfig(configFile)
 		if err != nil {
-			log.Fatalf("Error reading config.yaml: %v", err)
+			log.Fatal(err)
 		}
-		config := HalfLifeConfig{}
-		err = yaml.Unmarshal(dat, &config)
+
+		notificationService, err := NewMultiNotificationService(config.Notifications)
 		if err != nil {
-			log.Fatalf("Error parsing config.yaml: %v", err)
+			log.Fatal(err)
 		}
-		config.getUnsetDefaults()
 
-		if config.Notifications == nil {
-			panic("Notifications configuration is not present in config.yaml")
+		stateFile, _ := cmd.Flags().GetString("state")
+		stateStore, err := loadStateStore(stateFile)
+		if err != nil {
+			log.Fatalf("Error reading state file: %v", err)
 		}
+		stateStore.migrateStatusMessageIDs(config)
 
-		writeConfigMutex := sync.Mutex{}
-		// TODO implement more notification services e.g. slack, email
-		var notificationService NotificationService
-		switch config.Notifications.Service {
-		case "discord":
-			if config.Notifications.Discord == nil {
-				panic("Discord configuration not present in config.yaml")
-			}
-			notificationService = NewDiscordNotificationService(config.Notifications.Discord.Webhook.ID, config.Notifications.Discord.Webhook.Token)
-		default:
-			if config.Notifications.Service == "" {
-				panic("Notification service not configured in config.yaml")
-			}
-			panic(fmt.Sprintf("Notification service not supported: %s", config.Notifications.Service))
+		metricsAddress, _ := cmd.Flags().GetString("metrics-address")
+		if metricsAddress != "" {
+			serveMetrics(metricsAddress)
 		}
 
-		alertState := make(map[string]*ValidatorAlertState)
-		for i, vm := range config.Validators {
-			alertState[vm.Name] = &ValidatorAlertState{
-				AlertTypeCounts:            make(map[AlertType]int64),
-				SentryGRPCErrorCounts:      make(map[string]int64),
-				SentryOutOfSyncErrorCounts: make(map[string]int64),
-				SentryHaltErrorCounts:      make(map[string]int64),
-				SentryLatestHeight:         make(map[string]int64),
-			}
-			alertStateLock := sync.Mutex{}
-			if i == len(config.Validators)-1 {
-				runMonitor(notificationService, alertState[vm.Name], &alertStateLock, configFile, &config, vm, &writeConfigMutex)
-			} else {
-				go runMonitor(notificationService, alertState[vm.Name], &alertStateLock, configFile, &config, vm, &writeConfigMutex)
-			}
-		}
+		monitor := newMonitor(configFile, stateStore)
+		monitor.apply(config, notificationService)
+		monitor.watchConfig()
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(monitorCmd)
 	monitorCmd.Flags().StringP("file", "f", configFilePath, "File path to config yaml")
+	monitorCmd.Flags().StringP("state", "s", stateFilePath, "File path to state file, used to persist alert state across restarts")
+	monitorCmd.Flags().StringP("metrics-address", "m", "", "Address to serve prometheus metrics on at /metrics, e.g. :9100 (disabled if empty)")
+}
+
+// Monitor runs a monitor for each validator in the config,
+// and applies reloaded configs to the running monitors.
+type Monitor struct {
+	configFile    string
+	stateStore    *StateStore
+	reloadErrored bool
+
+	mutex               sync.RWMutex
+	config              *HalfLifeConfig
+	notificationService NotificationService
+	validators          map[string]*validatorRunner
+}
+
+type validatorRunner struct {
+	vm             *ValidatorMonitor // guarded by Monitor mutex, replaced when config is reloaded
+	alertState     *ValidatorAlertState
+	alertStateLock sync.Mutex
+	stop           chan struct{}
+
+	blocks      *blockWindow
+	blockStream *blockStream // only used by the validator's monitor
+	rpcIndex    int          // rpc endpoint in use, only used by the validator's monitor
+}
+
+func newMonitor(configFile string, stateStore *StateStore) *Monitor {
+	return &Monitor{
+		configFile: configFile,
+		stateStore: stateStore,
+		validators: make(map[string]*validatorRunner),
+	}
+}
+
+// config and notification service to use for the next check of the validator
+func (m *Monitor) current(runner *validatorRunner) (NotificationService, *HalfLifeConfig, *ValidatorMonitor) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.notificationService, m.config, runner.vm
+}
+
+func (runner *validatorRunner) stopped() bool {
+	select {
+	case <-runner.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// starts monitors for new validators, stops monitors for removed validators and drops their saved state,
+// and applies the config to the remaining monitors while keeping their alert state.
+func (m *Monitor) apply(config *HalfLifeConfig, notificationService NotificationService) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.config = config
+	m.notificationService = notificationService
+
+	configured := make(map[string]bool)
+	for _, vm := range config.Validators {
+		configured[vm.Name] = true
+		if runner, ok := m.validators[vm.Name]; ok {
+			removeChangedValidatorMetrics(runner.vm, vm)
+			if runner.vm.ChainID != vm.ChainID {
+				runner.blocks.reset()
+			}
+			runner.vm = vm
+			runner.alertStateLock.Lock()
+			runner.alertState.pruneSentries(vm)
+			runner.alertStateLock.Unlock()
+			continue
+		}
+		runner := &validatorRunner{
+			vm:         vm,
+			alertState: m.stateStore.alertState(vm.Name),
+			stop:       make(chan struct{}),
+			blocks:     newBlockWindow(),
+		}
+		m.validators[vm.Name] = runner
+		fmt.Printf("Starting monitor for validator: %s\n", vm.Name)
+		go runMonitor(m, runner)
+	}
+
+	for name, runner := range m.validators {
+		if !configured[name] {
+			fmt.Printf("Stopping monitor for validator: %s\n", name)
+			close(runner.stop)
+			delete(m.validators, name)
+			removeValidatorMetrics(runner.vm)
+		}
+	}
+	m.stateStore.pruneValidators(config)
 }
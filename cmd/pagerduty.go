@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+	pagerDutyEventActionTrigger = "trigger"
+	pagerDutyEventActionResolve = "resolve"
+)
+
+type PagerDutyNotificationService struct {
+	routingKey string
+	eventsURL  string
+	httpClient *http.Client
+}
+
+type pagerDutyPayload struct {
+	Summary   string `json:"summary"`
+	Source    string `json:"source"`
+	Severity  string `json:"severity"`
+	Component string `json:"component,omitempty"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+// config.EventsURL can be set to point the service at a stand-in for the PagerDuty Events API
+func NewPagerDutyNotificationService(config *PagerDutyConfig) *PagerDutyNotificationService {
+	eventsURL := config.EventsURL
+	if eventsURL == "" {
+		eventsURL = defaultPagerDutyEventsURL
+	}
+	return &PagerDutyNotificationService{
+		routingKey: config.RoutingKey,
+		eventsURL:  eventsURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func getPagerDutySeverityForAlertLevel(alertLevel AlertLevel) string {
+	switch alertLevel {
+	case alertLevelNone:
+		return "info"
+	case alertLevelWarning:
+		return "warning"
+	case alertLevelCritical:
+		return "critical"
+	default:
+		return "error"
+	}
+}
+
+// incidents for the same source (validator) and alert are deduplicated, so re-notifying an active alert does not open a new incident
+func pagerDutyDedupKey(source string, alertKey string) string {
+	return fmt.Sprintf("halflife-%s-%s", source, alertKey)
+}
+
+func (service *PagerDutyNotificationService) sendEvent(event pagerDutyEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*4))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, service.eventsURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := service.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("pagerduty %s event for %s returned http status %d", event.EventAction, event.DedupKey, res.StatusCode)
+	}
+	return nil
+}
+
+// implements NotificationService interface
+func (service *PagerDutyNotificationService) UpdateValidatorRealtimeStatus(
+	config *HalfLifeConfig,
+	vm *ValidatorMonitor,
+	stats ValidatorStats,
+	stateStore *StateStore,
+) {
+	// PagerDuty only tracks incidents, there is no realtime status to update
+}
+
+// implements NotificationService interface
+func (service *PagerDutyNotificationService) SendValidatorAlertNotification(
+	config *HalfLifeConfig,
+	vm *ValidatorMonitor,
+	stats ValidatorStats,
+	alertNotification *ValidatorAlertNotification,
+) error {
+	return service.sendAlertNotification(vm.Name, vm.ChainID, getAlertTitle(stats, vm), alertNotification)
+}
+
+// implements NotificationService interface
+func (service *PagerDutyNotificationService) SendAlertNotification(title string, alertNotification *ValidatorAlertNotification) error {
+	return service.sendAlertNotification("halflife", "", title, alertNotification)
+}
+
+func (service *PagerDutyNotificationService) sendAlertNotification(
+	source string,
+	component string,
+	title string,
+	alertNotification *ValidatorAlertNotification,
+) error {
+	var sendErr error
+	for i, alert := range alertNotification.Alerts {
+		if alertNotification.AlertLevels[i] == alertLevelNone {
+			// informational notices, e.g. a sentry version change, are not incidents and would never be resolved
+			continue
+		}
+		err := service.sendEvent(pagerDutyEvent{
+			RoutingKey:  service.routingKey,
+			EventAction: pagerDutyEventActionTrigger,
+			DedupKey:    pagerDutyDedupKey(source, alertNotification.AlertKeys[i]),
+			Payload: &pagerDutyPayload{
+				Summary:   fmt.Sprintf("%s: %s", title, strings.TrimSpace(alert)),
+				Source:    source,
+				Severity:  getPagerDutySeverityForAlertLevel(alertNotification.AlertLevels[i]),
+				Component: component,
+			},
+		})
+		if err != nil {
+			sendErr = err
+		}
+	}
+
+	for _, clearedAlertKey := range alertNotification.ClearedAlertKeys {
+		err := service.sendEvent(pagerDutyEvent{
+			RoutingKey:  service.routingKey,
+			EventAction: pagerDutyEventActionResolve,
+			DedupKey:    pagerDutyDedupKey(source, clearedAlertKey),
+		})
+		if err != nil {
+			sendErr = err
+		}
+	}
+	return sendErr
+}
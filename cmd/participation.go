@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	defaultParticipationThreshold = 75.0 // percentage of voting power, alerting before participation reaches the 2/3 needed for blocks
+	networkWideMissParticipation  = 90.0 // a missed block is network-wide when less than this percentage of the other voting power signed it
+)
+
+// voting power of the validator set, used to weigh the signatures in each block.
+// The latest validator set is used for every block checked, so participation is approximate around validator set changes.
+type participationSet struct {
+	powers map[string]int64
+	total  int64
+}
+
+func newParticipationSet(validatorSet []*types.Validator) *participationSet {
+	p := &participationSet{powers: make(map[string]int64, len(validatorSet))}
+	for _, validator := range validatorSet {
+		p.powers[validator.Address.String()] = validator.VotingPower
+		p.total += validator.VotingPower
+	}
+	return p
+}
+
+// percentage of the voting power that committed the block, and the same for the voting power other than the validator
+func (p *participationSet) participation(block *blockRecord, hexAddress []byte) (all float64, others float64) {
+	var signed int64
+	for _, voter := range block.LastCommit.Signatures {
+		if voter.BlockIDFlag == types.BlockIDFlagCommit {
+			signed += p.powers[voter.ValidatorAddress.String()]
+		}
+	}
+	validatorPower := p.powers[fmt.Sprintf("%X", hexAddress)]
+	if p.total == 0 || p.total == validatorPower {
+		return 0, 0
+	}
+	all = float64(signed) / float64(p.total) * 100
+	if getCommitVote(block, hexAddress) == types.BlockIDFlagCommit {
+		signed -= validatorPower
+	}
+	others = float64(signed) / float64(p.total-validatorPower) * 100
+	return
+}
+
+func (vm *ValidatorMonitor) participationThreshold() float64 {
+	if vm.ParticipationThreshold == nil {
+		return defaultParticipationThreshold
+	}
+	return *vm.ParticipationThreshold
+}
+
+// keeps the lowest participation of the blocks checked
+func (stats *ValidatorStats) setMinParticipation(participation float64, height int64) {
+	if !stats.ParticipationChecked || participation < stats.MinParticipation {
+		stats.MinParticipation = participation
+		stats.MinParticipationHeight = height
+	}
+	stats.ParticipationChecked = true
+}
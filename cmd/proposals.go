@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"reflect"
+
+	"github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	defaultMissedProposalsThreshold int64 = 2 // a single missed round can be a slow network rather than the validator
+)
+
+// proposals of the validator in the recent blocks checked
+type ProposalStats struct {
+	Proposed int              // blocks checked that the validator proposed
+	Expected float64          // blocks the validator was expected to propose by its share of voting power, 0 if unknown
+	Missed   []MissedProposal // rounds the validator was the proposer of but a later round was committed
+}
+
+type MissedProposal struct {
+	Height     int64
+	Round      int32
+	ProposedBy string // proposer of the committed block
+}
+
+func (vm *ValidatorMonitor) missedProposalsThreshold() int64 {
+	if vm.MissedProposalsThreshold == nil {
+		return defaultMissedProposalsThreshold
+	}
+	return *vm.MissedProposalsThreshold
+}
+
+// share of the voting power held by the validator
+func (p *participationSet) votingPowerShare(hexAddress []byte) float64 {
+	if p.total == 0 {
+		return 0
+	}
+	return float64(p.powers[types.Address(hexAddress).String()]) / float64(p.total)
+}
+
+// Proposers of the rounds of height before the round the block was committed in.
+// The validator set at a height has the proposer priorities left after the round 0 proposer was chosen, so later rounds are found
+// by incrementing them, and the round 0 proposer by incrementing the validator set of the previous height, which is only known when
+// the voting powers did not change between the heights. Unknown proposers are nil.
+// Proposers are kept with the window so validator sets are only fetched once for each height that needed more than one round.
+func (w *blockWindow) skippedProposers(node rpcclient.Client, height int64, round int32) ([]types.Address, error) {
+	if round == 0 {
+		return nil, nil
+	}
+	w.mutex.Lock()
+	proposers, ok := w.roundProposers[height]
+	w.mutex.Unlock()
+	if ok {
+		return proposers, nil
+	}
+	validators, err := getValidatorSet(node, &height)
+	if err != nil {
+		return nil, err
+	}
+	if len(validators) == 0 {
+		return nil, nil
+	}
+	proposers = make([]types.Address, round)
+	if height > 1 {
+		previousHeight := height - 1
+		previousValidators, err := getValidatorSet(node, &previousHeight)
+		if err != nil {
+			return nil, err
+		}
+		if sameVotingPowers(previousValidators, validators) {
+			proposers[0] = getRoundProposer(previousValidators, 1)
+		}
+	}
+	for r := int32(1); r < round; r++ {
+		proposers[r] = getRoundProposer(validators, r)
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if height > w.maxHeight-w.keep {
+		w.roundProposers[height] = proposers
+	}
+	return proposers, nil
+}
+
+// proposer after incrementing the proposer priorities of the validators, as consensus does for each round
+func getRoundProposer(validators []*types.Validator, increments int32) types.Address {
+	set := (&types.ValidatorSet{Validators: validators}).Copy()
+	set.IncrementProposerPriority(increments)
+	return set.GetProposer().Address
+}
+
+func sameVotingPowers(a []*types.Validator, b []*types.Validator) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	powers := make(map[string]int64, len(a))
+	for _, validator := range a {
+		powers[validator.Address.String()] = validator.VotingPower
+	}
+	for _, validator := range b {
+		if power, ok := powers[validator.Address.String()]; !ok || power != validator.VotingPower {
+			return false
+		}
+	}
+	return true
+}
+
+// adds the rounds of the block's height that the validator was the proposer of but were not committed
+func (s *ProposalStats) addSkippedRounds(block *blockRecord, proposers []types.Address, hexAddress []byte) {
+	for round, proposer := range proposers {
+		if proposer != nil && reflect.DeepEqual(proposer, bytes.HexBytes(hexAddress)) {
+			s.Missed = append(s.Missed, MissedProposal{Height: block.Height, Round: int32(round), ProposedBy: block.ProposerAddress.String()})
+		}
+	}
+}
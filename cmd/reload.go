@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	configReloadDebounce    = time.Second // editors can write the config file several times for a single save
+	configReloadAlertTitle  = "HalfLife"
+	configReloadAlertKey    = "configReload"
+	configReloadClearedText = "config reload error"
+)
+
+// reloads config.yaml when it is changed or SIGHUP is received. Blocks forever.
+func (m *Monitor) watchConfig() {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	var fileEvents chan fsnotify.Event
+	var fileErrors chan error
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		fmt.Printf("Error watching config file, only reloading on SIGHUP: %v\n", err)
+	} else {
+		defer watcher.Close()
+		// watch the directory rather than the file, so that the config is still watched after editors replace the file
+		if err := watcher.Add(filepath.Dir(m.configFile)); err != nil {
+			fmt.Printf("Error watching config file, only reloading on SIGHUP: %v\n", err)
+		} else {
+			fileEvents = watcher.Events
+			fileErrors = watcher.Errors
+		}
+	}
+
+	configFile := filepath.Clean(m.configFile)
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-sighup:
+			fmt.Println("Received SIGHUP, reloading config")
+			m.reload()
+		case event := <-fileEvents:
+			if filepath.Clean(event.Name) == configFile && event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				debounce = time.After(configReloadDebounce)
+			}
+		case err := <-fileErrors:
+			fmt.Printf("Error watching config file: %v\n", err)
+		case <-debounce:
+			debounce = nil
+			fmt.Println("Config file changed, reloading config")
+			m.reload()
+		}
+	}
+}
+
+// invalid configs are not applied, the previous config keeps running and an error notification is sent
+func (m *Monitor) reload() {
+	config, err := loadConfig(m.configFile)
+	var notificationService NotificationService
+	if err == nil {
+		notificationService, err = NewMultiNotificationService(config.Notifications)
+	}
+	if err != nil {
+		fmt.Printf("Error reloading config, continuing with previous config: %v\n", err)
+		m.mutex.RLock()
+		notificationService = m.notificationService
+		m.mutex.RUnlock()
+		notificationService.SendAlertNotification(configReloadAlertTitle, &ValidatorAlertNotification{
+			Alerts:      []string{fmt.Sprintf("error reloading %s, continuing with previous config: %v", m.configFile, err)},
+			AlertKeys:   []string{configReloadAlertKey},
+			AlertLevels: []AlertLevel{alertLevelWarning},
+			AlertLevel:  alertLevelWarning,
+		})
+		m.reloadErrored = true
+		return
+	}
+
+	m.stateStore.migrateStatusMessageIDs(config)
+	m.apply(config, notificationService)
+	fmt.Printf("Reloaded config: %s\n", m.configFile)
+
+	if m.reloadErrored {
+		m.reloadErrored = false
+		notificationService.SendAlertNotification(configReloadAlertTitle, &ValidatorAlertNotification{
+			ClearedAlerts:      []string{configReloadClearedText},
+			ClearedAlertKeys:   []string{configReloadAlertKey},
+			ClearedAlertLevels: []AlertLevel{alertLevelWarning},
+		})
+	}
+}
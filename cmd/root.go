@@ -17,7 +17,7 @@ Checks for scenarios such as:
 - Jailed status
 - Tombstoned status
 
-Discord messages are created in the configured webhook channel for:
+Discord or Slack messages are created in the configured channel for:
 - Current validator status
 - Detected alerts
 `,
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	defaultRPCQuorum = 1
+)
+
+type RPCEndpointStats struct {
+	Name       string
+	Height     int64
+	CatchingUp bool
+	Error      bool
+	Current    bool // endpoint used for the next check
+}
+
+// rpc and rpcs combined, in order and without duplicates
+func (vm *ValidatorMonitor) rpcEndpoints() []Endpoint {
+	var endpoints []Endpoint
+	var urls []string
+	for _, rpc := range append([]Endpoint{vm.RPC}, vm.RPCs...) {
+		if rpc.URL != "" && !containsString(urls, rpc.URL) {
+			endpoints = append(endpoints, rpc)
+			urls = append(urls, rpc.URL)
+		}
+	}
+	return endpoints
+}
+
+func (vm *ValidatorMonitor) rpcQuorum() int {
+	if vm.RPCQuorum == nil {
+		return defaultRPCQuorum
+	}
+	return *vm.RPCQuorum
+}
+
+// name of the endpoint shown in notifications, without the path or credentials that may be in the url
+func getRPCEndpointName(rpc string) string {
+	u, err := url.Parse(rpc)
+	if err != nil || u.Host == "" {
+		return rpc
+	}
+	return u.Host
+}
+
+// rpc endpoint to use for the next check of the validator. Only called from the validator's monitor.
+func (runner *validatorRunner) currentRPC(vm *ValidatorMonitor) Endpoint {
+	endpoints := vm.rpcEndpoints()
+	if len(endpoints) == 0 {
+		return Endpoint{}
+	}
+	return endpoints[runner.rpcIndex%len(endpoints)]
+}
+
+// fails over to the next rpc endpoint. Only called from the validator's monitor.
+// The endpoints are checked so the next endpoint in order that responds and is not catching up is used,
+// or just the next endpoint if none of them are healthy.
+func (runner *validatorRunner) rotateRPC(vm *ValidatorMonitor) {
+	endpoints := vm.rpcEndpoints()
+	if len(endpoints) <= 1 {
+		return
+	}
+	endpointStats := monitorRPCEndpoints(vm)
+	current := runner.rpcIndex % len(endpoints)
+	for offset := 1; offset < len(endpoints); offset++ {
+		next := (current + offset) % len(endpoints)
+		if endpointStats[next].healthy() {
+			runner.rpcIndex = next
+			return
+		}
+	}
+	runner.rpcIndex = (current + 1) % len(endpoints)
+}
+
+func (s *RPCEndpointStats) healthy() bool {
+	return !s.Error && !s.CatchingUp
+}
+
+// errors from the rpc endpoint that another endpoint may not have
+func isRPCEndpointError(err error) bool {
+	switch err.(type) {
+	case *GenericRPCError, *OutOfSyncError:
+		return true
+	default:
+		return false
+	}
+}
+
+// status of every rpc endpoint, checked concurrently
+func monitorRPCEndpoints(vm *ValidatorMonitor) []*RPCEndpointStats {
+	endpoints := vm.rpcEndpoints()
+	endpointStats := make([]*RPCEndpointStats, len(endpoints))
+	wg := sync.WaitGroup{}
+	wg.Add(len(endpoints))
+	for i, rpc := range endpoints {
+		endpointStats[i] = &RPCEndpointStats{Name: getRPCEndpointName(rpc.URL)}
+		go func(rpc Endpoint, endpointStats *RPCEndpointStats) {
+			defer wg.Done()
+			client, err := newClient(rpc)
+			if err != nil {
+				endpointStats.Error = true
+				return
+			}
+			statusCtx, statusCtxCancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+			status, err := client.Status(statusCtx)
+			statusCtxCancel()
+			if err != nil {
+				endpointStats.Error = true
+				return
+			}
+			endpointStats.Height = status.SyncInfo.LatestBlockHeight
+			endpointStats.CatchingUp = status.SyncInfo.CatchingUp
+		}(rpc, endpointStats[i])
+	}
+	wg.Wait()
+	return endpointStats
+}
+
+// whether another rpc endpoint agrees that the validator is jailed, or nil if the endpoint could not be checked
+func getRPCJailedVerdict(vm *ValidatorMonitor, rpc Endpoint) *bool {
+	client, err := getCosmosClient(rpc, vm.ChainID)
+	if err != nil {
+		return nil
+	}
+	valInfo, err := getSigningInfo(client, vm.Address)
+	if err != nil {
+		return nil
+	}
+	jailed := valInfo.ValSigningInfo.JailedUntil.After(time.Now())
+	return &jailed
+}
+
+// Whether another rpc endpoint agrees that the validator missed more than the threshold in the same recent blocks,
+// or nil if the endpoint could not be checked. Only the blocks missed on the endpoint in use are fetched, until the verdict is known.
+func getRPCMissedBlocksVerdict(vm *ValidatorMonitor, rpc Endpoint, stats *ValidatorStats, missedBlocksThreshold int64) *bool {
+	_, hexAddress, err := bech32.DecodeAndConvert(vm.Address)
+	if err != nil {
+		return nil
+	}
+	client, err := getCosmosClient(rpc, vm.ChainID)
+	if err != nil {
+		return nil
+	}
+	node, err := client.GetNode()
+	if err != nil {
+		return nil
+	}
+	var missed int64
+	for i, height := range stats.RecentMissedHeights {
+		if missed > missedBlocksThreshold || missed+int64(len(stats.RecentMissedHeights)-i) <= missedBlocksThreshold {
+			break
+		}
+		height := height
+		blockCtx, blockCtxCancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+		block, err := node.Block(blockCtx, &height)
+		blockCtxCancel()
+		if err != nil {
+			return nil
+		}
+		if getCommitVote(newBlockRecord(block.Block), hexAddress) == types.BlockIDFlagAbsent {
+			missed++
+		}
+	}
+	verdict := missed > missedBlocksThreshold
+	return &verdict
+}
+
+// Missed block and jailed verdicts are only kept when at least rpc-quorum endpoints agree.
+// Endpoints that cannot be checked do not count towards the quorum.
+func confirmVerdicts(vm *ValidatorMonitor, rpc Endpoint, stats *ValidatorStats, missedBlocksThreshold int64, errs []IgnorableError) []IgnorableError {
+	quorum := vm.rpcQuorum()
+	if quorum <= 1 {
+		return errs
+	}
+	confirmed := func(verdict func(rpc Endpoint) *bool) bool {
+		agree := 1
+		for _, otherRPC := range vm.rpcEndpoints() {
+			if agree >= quorum {
+				break
+			}
+			if otherRPC.URL == rpc.URL {
+				continue
+			}
+			if v := verdict(otherRPC); v != nil && *v {
+				agree++
+			}
+		}
+		return agree >= quorum
+	}
+	var confirmedErrs []IgnorableError
+	for _, err := range errs {
+		switch err.(type) {
+		case *JailedError:
+			if !confirmed(func(otherRPC Endpoint) *bool { return getRPCJailedVerdict(vm, otherRPC) }) {
+				fmt.Printf("Jailed verdict for %s not confirmed by rpc quorum\n", vm.Name)
+				continue
+			}
+		case *MissedRecentBlocksError:
+			if !confirmed(func(otherRPC Endpoint) *bool {
+				return getRPCMissedBlocksVerdict(vm, otherRPC, stats, missedBlocksThreshold)
+			}) {
+				fmt.Printf("Missed blocks verdict for %s not confirmed by rpc quorum\n", vm.Name)
+				continue
+			}
+		}
+		confirmedErrs = append(confirmedErrs, err)
+	}
+	return confirmedErrs
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+const (
+	defaultSentryMinPeers = 3
+)
+
+func (vm *ValidatorMonitor) sentryMinPeers() int {
+	if vm.SentryMinPeers == nil {
+		return defaultSentryMinPeers
+	}
+	return *vm.SentryMinPeers
+}
+
+func (s Sentry) validate() error {
+	if s.GRPC.URL == "" && s.RPC.URL == "" && s.LCD.URL == "" {
+		return errors.New("grpc, rpc or lcd must be configured")
+	}
+	for name, endpoint := range map[string]Endpoint{"grpc": s.GRPC, "rpc": s.RPC, "lcd": s.LCD} {
+		if endpoint.URL == "" {
+			continue
+		}
+		if err := endpoint.validate(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Fills the height, version, catching up and peers of the sentry from its configured endpoints, and returns the latest block time.
+// The latest block comes from grpc, then rpc, then lcd. Catching up is only known from the rpc or lcd, and peers from the rpc.
+// Errors getting catching up and peers are returned in syncErr, since the sentry's blocks are still known.
+func getSentryStats(sentry Sentry, sentryStats *SentryStats) (latestBlockTime time.Time, syncErr error, err error) {
+	var status *coretypes.ResultStatus
+	switch {
+	case sentry.GRPC.URL != "":
+		nodeInfo, syncInfo, err := getSentryInfo(sentry.GRPC)
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("grpc: %w", err)
+		}
+		sentryStats.Height = syncInfo.Block.Header.Height
+		sentryStats.Version = nodeInfo.ApplicationVersion.GetVersion()
+		sentryStats.NodeID = nodeInfo.DefaultNodeInfo.GetDefaultNodeID()
+		latestBlockTime = syncInfo.Block.Header.Time
+	case sentry.RPC.URL != "":
+		status, err = getSentryRPCLatestBlock(sentry.RPC, sentryStats)
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("rpc: %w", err)
+		}
+		latestBlockTime = status.SyncInfo.LatestBlockTime
+	default:
+		latestBlockTime, err = getSentryLCDLatestBlock(sentry.LCD, sentryStats)
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("lcd: %w", err)
+		}
+	}
+	switch {
+	case sentry.RPC.URL != "":
+		if err := getSentryRPCSyncInfo(sentry.RPC, status, sentryStats); err != nil {
+			return latestBlockTime, fmt.Errorf("rpc: %w", err), nil
+		}
+	case sentry.LCD.URL != "":
+		if err := getSentryLCDSyncing(sentry.LCD, sentryStats); err != nil {
+			return latestBlockTime, fmt.Errorf("lcd: %w", err), nil
+		}
+	}
+	return latestBlockTime, nil, nil
+}
+
+// latest block from the rpc /status and application version from /abci_info, returns the status so it can be reused
+func getSentryRPCLatestBlock(rpc Endpoint, sentryStats *SentryStats) (*coretypes.ResultStatus, error) {
+	client, err := newClient(rpc)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*sentryGRPCTimeoutSeconds))
+	defer cancel()
+	status, err := client.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	abciInfo, err := client.ABCIInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sentryStats.Height = status.SyncInfo.LatestBlockHeight
+	sentryStats.Version = abciInfo.Response.Version
+	sentryStats.NodeID = string(status.NodeInfo.DefaultNodeID)
+	return status, nil
+}
+
+// catching up from the rpc /status, unless it was already fetched, and peers from /net_info
+func getSentryRPCSyncInfo(rpc Endpoint, status *coretypes.ResultStatus, sentryStats *SentryStats) error {
+	client, err := newClient(rpc)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*sentryGRPCTimeoutSeconds))
+	defer cancel()
+	if status == nil {
+		status, err = client.Status(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	netInfo, err := client.NetInfo(ctx)
+	if err != nil {
+		return err
+	}
+	sentryStats.CatchingUp = status.SyncInfo.CatchingUp
+	sentryStats.PeersChecked = true
+	sentryStats.Peers = netInfo.NPeers
+	return nil
+}
+
+type lcdNodeInfoResponse struct {
+	DefaultNodeInfo struct {
+		DefaultNodeID string `json:"default_node_id"`
+	} `json:"default_node_info"`
+	ApplicationVersion struct {
+		Version string `json:"version"`
+	} `json:"application_version"`
+}
+
+type lcdLatestBlockResponse struct {
+	Block struct {
+		Header struct {
+			Height string    `json:"height"`
+			Time   time.Time `json:"time"`
+		} `json:"header"`
+	} `json:"block"`
+}
+
+type lcdSyncingResponse struct {
+	Syncing bool `json:"syncing"`
+}
+
+// latest block and application version from the lcd tendermint service
+func getSentryLCDLatestBlock(lcd Endpoint, sentryStats *SentryStats) (time.Time, error) {
+	var nodeInfo lcdNodeInfoResponse
+	if err := getLCD(lcd, "/cosmos/base/tendermint/v1beta1/node_info", &nodeInfo); err != nil {
+		return time.Time{}, err
+	}
+	var latestBlock lcdLatestBlockResponse
+	if err := getLCD(lcd, "/cosmos/base/tendermint/v1beta1/blocks/latest", &latestBlock); err != nil {
+		return time.Time{}, err
+	}
+	height, err := strconv.ParseInt(latestBlock.Block.Header.Height, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid latest block height: %w", err)
+	}
+	sentryStats.Height = height
+	sentryStats.Version = nodeInfo.ApplicationVersion.Version
+	sentryStats.NodeID = nodeInfo.DefaultNodeInfo.DefaultNodeID
+	return latestBlock.Block.Header.Time, nil
+}
+
+func getSentryLCDSyncing(lcd Endpoint, sentryStats *SentryStats) error {
+	var syncing lcdSyncingResponse
+	if err := getLCD(lcd, "/cosmos/base/tendermint/v1beta1/syncing", &syncing); err != nil {
+		return err
+	}
+	sentryStats.CatchingUp = syncing.Syncing
+	return nil
+}
+
+// decodes the JSON response of a GET request to the lcd
+func getLCD(lcd Endpoint, path string, response interface{}) error {
+	httpClient := &http.Client{
+		Transport: &http.Transport{},
+		Timeout:   time.Duration(time.Second * sentryGRPCTimeoutSeconds),
+	}
+	if err := lcd.configureHTTPClient(httpClient); err != nil {
+		return err
+	}
+	res, err := httpClient.Get(strings.TrimSuffix(lcd.URL, "/") + path)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %s", path, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(response)
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSlackAPIURL = "https://slack.com/api"
+)
+
+type SlackNotificationService struct {
+	name       string
+	config     *SlackChannelConfig
+	apiURL     string
+	httpClient *http.Client
+	postMutex  *sync.Mutex
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackMessage struct {
+	Channel     string            `json:"channel"`
+	TS          string            `json:"ts,omitempty"`
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+// error returned by the Slack Web API for a request it received, as opposed to a failure to reach it
+type slackAPIError struct {
+	method string
+	code   string
+}
+
+func (e *slackAPIError) Error() string {
+	return fmt.Sprintf("slack %s failed: %s", e.method, e.code)
+}
+
+type slackResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// config.APIURL can be set to point the service at a stand-in for the Slack Web API
+func NewSlackNotificationService(name string, config *SlackChannelConfig) *SlackNotificationService {
+	apiURL := config.APIURL
+	if apiURL == "" {
+		apiURL = defaultSlackAPIURL
+	}
+	return &SlackNotificationService{
+		name:       name,
+		config:     config,
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		postMutex:  &sync.Mutex{},
+	}
+}
+
+var slackStatusFormat = statusFormat{
+	bold: func(s string) string { return fmt.Sprintf("*%s*", s) },
+	timestamp: func(t time.Time) string {
+		return fmt.Sprintf("<!date^%d^{date_short_pretty} {time_secs}|%s>", t.Unix(), t.UTC().Format(time.RFC1123))
+	},
+}
+
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func slackColorForAlertLevel(alertLevel AlertLevel) string {
+	return fmt.Sprintf("#%06X", getColorForAlertLevel(alertLevel))
+}
+
+func slackAttachmentFor(title string, description string, color string) slackAttachment {
+	return slackAttachment{
+		Color: color,
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: title}},
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: description}},
+		},
+	}
+}
+
+func (service *SlackNotificationService) call(method string, message slackMessage) (*slackResponse, error) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*4))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s", service.apiURL, method), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", service.config.Token))
+
+	service.postMutex.Lock()
+	res, err := service.httpClient.Do(req)
+	service.postMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("slack %s returned http status %d", method, res.StatusCode)
+	}
+
+	slackRes := slackResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&slackRes); err != nil {
+		return nil, err
+	}
+	if !slackRes.OK {
+		return nil, &slackAPIError{method, slackRes.Error}
+	}
+	return &slackRes, nil
+}
+
+// implements NotificationService interface
+func (service *SlackNotificationService) UpdateValidatorRealtimeStatus(
+	config *HalfLifeConfig,
+	vm *ValidatorMonitor,
+	stats ValidatorStats,
+	stateStore *StateStore,
+) {
+	title, description := getCurrentStatsTitleAndDescription(stats, vm, slackStatusFormat)
+	message := slackMessage{
+		Channel:     service.config.Channel,
+		Text:        title,
+		Attachments: []slackAttachment{slackAttachmentFor(title, description, slackColorForAlertLevel(stats.AlertLevel))},
+	}
+
+	if statusMessageTS, ok := stateStore.statusMessageID(vm.Name, service.name); ok {
+		message.TS = statusMessageTS
+		_, err := service.call("chat.update", message)
+		if err == nil {
+			return
+		}
+		fmt.Printf("Error updating slack message: %v\n", err)
+		// the status message can no longer be updated, e.g. it was deleted, so a new one is posted in its place.
+		// Other errors may be temporary, so the update is retried on the next check.
+		if _, ok := err.(*slackAPIError); !ok {
+			return
+		}
+		message.TS = ""
+	}
+
+	res, err := service.call("chat.postMessage", message)
+	if err != nil {
+		fmt.Printf("Error sending slack message: %v\n", err)
+		return
+	}
+	fmt.Printf("Saved message timestamp: %s\n", res.TS)
+	stateStore.saveStatusMessageID(vm.Name, service.name, res.TS)
+}
+
+// implements NotificationService interface
+func (service *SlackNotificationService) SendValidatorAlertNotification(
+	config *HalfLifeConfig,
+	vm *ValidatorMonitor,
+	stats ValidatorStats,
+	alertNotification *ValidatorAlertNotification,
+) error {
+	return service.SendAlertNotification(getAlertTitle(stats, vm), alertNotification)
+}
+
+// implements NotificationService interface
+func (service *SlackNotificationService) SendAlertNotification(title string, alertNotification *ValidatorAlertNotification) error {
+	var sendErr error
+	tagUserGroups := ""
+	for _, userGroupID := range service.config.AlertUserGroupIDs {
+		tagUserGroups += fmt.Sprintf("<!subteam^%s> ", userGroupID)
+	}
+
+	if len(alertNotification.Alerts) > 0 {
+		alertString := ""
+		for _, alert := range alertNotification.Alerts {
+			alertString += fmt.Sprintf("\n• %s", slackEscape(alert))
+		}
+		text := title
+		if alertNotification.AlertLevel > alertLevelWarning && tagUserGroups != "" {
+			text = fmt.Sprintf("%s %s", strings.Trim(tagUserGroups, " "), title)
+		}
+		_, err := service.call("chat.postMessage", slackMessage{
+			Channel: service.config.Channel,
+			Text:    text,
+			Attachments: []slackAttachment{
+				slackAttachmentFor(title, fmt.Sprintf("*Errors:*\n%s", strings.Trim(alertString, "\n")), slackColorForAlertLevel(alertNotification.AlertLevel)),
+			},
+		})
+		if err != nil {
+			sendErr = err
+		}
+	}
+
+	if len(alertNotification.ClearedAlerts) > 0 {
+		clearedAlertsString := ""
+		for _, alert := range alertNotification.ClearedAlerts {
+			clearedAlertsString += fmt.Sprintf("\n• %s", slackEscape(alert))
+		}
+		text := title
+		if alertNotification.NotifyForClear && tagUserGroups != "" {
+			text = fmt.Sprintf("%s %s", strings.Trim(tagUserGroups, " "), title)
+		}
+		_, err := service.call("chat.postMessage", slackMessage{
+			Channel: service.config.Channel,
+			Text:    text,
+			Attachments: []slackAttachment{
+				slackAttachmentFor(title, fmt.Sprintf("*Errors cleared:*\n%s", strings.Trim(clearedAlertsString, "\n")), slackColorForAlertLevel(alertLevelNone)),
+			},
+		})
+		if err != nil {
+			sendErr = err
+		}
+	}
+	return sendErr
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// slackStandIn is a local stand-in for the Slack Web API that records the requests it receives
+type slackStandIn struct {
+	mutex    sync.Mutex
+	requests []slackStandInRequest
+	// error code returned for a method, the request succeeds if there is none
+	errors map[string]string
+	server *httptest.Server
+}
+
+type slackStandInRequest struct {
+	method        string
+	authorization string
+	message       slackMessage
+}
+
+func newSlackStandIn(t *testing.T) *slackStandIn {
+	standIn := &slackStandIn{errors: make(map[string]string)}
+	standIn.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		message := slackMessage{}
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("invalid slack request body: %v", err)
+		}
+		method := r.URL.Path[1:]
+		standIn.mutex.Lock()
+		standIn.requests = append(standIn.requests, slackStandInRequest{method, r.Header.Get("Authorization"), message})
+		errorCode := standIn.errors[method]
+		count := len(standIn.requests)
+		standIn.mutex.Unlock()
+
+		res := slackResponse{OK: errorCode == "", Error: errorCode, Channel: message.Channel}
+		if res.OK {
+			res.TS = message.TS
+			if res.TS == "" {
+				res.TS = fmt.Sprintf("1650000001.%06d", count)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(standIn.server.Close)
+	return standIn
+}
+
+func (s *slackStandIn) methods() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	methods := make([]string, len(s.requests))
+	for i, request := range s.requests {
+		methods[i] = request.method
+	}
+	return methods
+}
+
+func (s *slackStandIn) request(i int) slackStandInRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests[i]
+}
+
+func newTestSlackService(t *testing.T, standIn *slackStandIn) (*SlackNotificationService, *StateStore) {
+	service := NewSlackNotificationService("slack", &SlackChannelConfig{
+		Token:             "xoxb-test",
+		Channel:           "C0123",
+		AlertUserGroupIDs: []string{"S0123"},
+		APIURL:            standIn.server.URL + "/",
+	})
+	stateStore, err := loadStateStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, stateStore
+}
+
+func assertMethods(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected slack calls %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected slack calls %v, got %v", want, got)
+		}
+	}
+}
+
+func TestSlackStatusMessageIsPostedThenUpdated(t *testing.T) {
+	standIn := newSlackStandIn(t)
+	service, stateStore := newTestSlackService(t, standIn)
+	vm := &ValidatorMonitor{Name: "validator", FullNode: true}
+
+	service.UpdateValidatorRealtimeStatus(&HalfLifeConfig{}, vm, ValidatorStats{}, stateStore)
+	ts, ok := stateStore.statusMessageID(vm.Name, "slack")
+	if !ok {
+		t.Fatal("status message timestamp was not saved")
+	}
+
+	service.UpdateValidatorRealtimeStatus(&HalfLifeConfig{}, vm, ValidatorStats{}, stateStore)
+	assertMethods(t, standIn.methods(), "chat.postMessage", "chat.update")
+	if update := standIn.request(1); update.message.TS != ts || update.message.Channel != "C0123" {
+		t.Fatalf("expected update of %s in C0123, got %s in %s", ts, update.message.TS, update.message.Channel)
+	}
+	if standIn.request(0).authorization != "Bearer xoxb-test" {
+		t.Fatalf("unexpected authorization header %q", standIn.request(0).authorization)
+	}
+}
+
+func TestSlackStatusMessageIsRepostedWhenUpdateFails(t *testing.T) {
+	standIn := newSlackStandIn(t)
+	service, stateStore := newTestSlackService(t, standIn)
+	vm := &ValidatorMonitor{Name: "validator", FullNode: true}
+	stateStore.saveStatusMessageID(vm.Name, "slack", "1650000000.000000")
+	standIn.errors["chat.update"] = "message_not_found"
+
+	service.UpdateValidatorRealtimeStatus(&HalfLifeConfig{}, vm, ValidatorStats{}, stateStore)
+	assertMethods(t, standIn.methods(), "chat.update", "chat.postMessage")
+	ts, _ := stateStore.statusMessageID(vm.Name, "slack")
+	if ts == "1650000000.000000" || ts == "" {
+		t.Fatalf("expected the new status message timestamp to be saved, got %q", ts)
+	}
+	if standIn.request(1).message.TS != "" {
+		t.Fatal("expected a new status message to be posted without a timestamp")
+	}
+}
+
+func TestSlackStatusMessageIsKeptWhenSlackIsUnreachable(t *testing.T) {
+	standIn := newSlackStandIn(t)
+	service, stateStore := newTestSlackService(t, standIn)
+	vm := &ValidatorMonitor{Name: "validator", FullNode: true}
+	stateStore.saveStatusMessageID(vm.Name, "slack", "1650000000.000000")
+	standIn.server.Close()
+
+	service.UpdateValidatorRealtimeStatus(&HalfLifeConfig{}, vm, ValidatorStats{}, stateStore)
+	if ts, _ := stateStore.statusMessageID(vm.Name, "slack"); ts != "1650000000.000000" {
+		t.Fatalf("expected the status message timestamp to be kept, got %q", ts)
+	}
+}
+
+func TestSlackAlertNotificationTagsUserGroupsAboveWarning(t *testing.T) {
+	standIn := newSlackStandIn(t)
+	service, _ := newTestSlackService(t, standIn)
+
+	service.SendAlertNotification("validator", &ValidatorAlertNotification{
+		Alerts:     []string{"validator is jailed"},
+		AlertKeys:  []string{string(alertTypeJailed)},
+		AlertLevel: alertLevelHigh,
+	})
+	service.SendAlertNotification("validator", &ValidatorAlertNotification{
+		ClearedAlerts:    []string{"jailed"},
+		ClearedAlertKeys: []string{string(alertTypeJailed)},
+	})
+
+	assertMethods(t, standIn.methods(), "chat.postMessage", "chat.postMessage")
+	if text := standIn.request(0).message.Text; text != "<!subteam^S0123> validator" {
+		t.Fatalf("expected alert to tag the user group, got %q", text)
+	}
+	if text := standIn.request(1).message.Text; text != "validator" {
+		t.Fatalf("expected cleared alert without NotifyForClear not to tag the user group, got %q", text)
+	}
+}
+
+func TestSlackAlertNotificationReturnsSendError(t *testing.T) {
+	standIn := newSlackStandIn(t)
+	service, _ := newTestSlackService(t, standIn)
+	standIn.errors["chat.postMessage"] = "channel_not_found"
+
+	err := service.SendAlertNotification("validator", &ValidatorAlertNotification{
+		Alerts:      []string{"validator is jailed"},
+		AlertKeys:   []string{string(alertTypeJailed)},
+		AlertLevels: []AlertLevel{alertLevelHigh},
+		AlertLevel:  alertLevelHigh,
+	})
+	if err == nil {
+		t.Fatal("expected the failed send to be returned so it is not counted as sent")
+	}
+}
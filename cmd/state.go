@@ -0,0 +1,349 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	stateFilePath = "./halflife-state.json"
+)
+
+// HalfLifeState is the runtime state that is persisted across restarts
+type HalfLifeState struct {
+	Validators map[string]*ValidatorState `json:"validators"`
+}
+
+type ValidatorState struct {
+	AlertState *ValidatorAlertState `json:"alertState"`
+	// realtime status message for each notification sink, keyed by sink name
+	StatusMessageIDs map[string]string `json:"statusMessageIDs,omitempty"`
+}
+
+// StateStore keeps the latest snapshot of the runtime state and writes it to the state file
+type StateStore struct {
+	file  string
+	state HalfLifeState
+	mutex sync.Mutex
+}
+
+func newValidatorAlertState() *ValidatorAlertState {
+	alertState := &ValidatorAlertState{}
+	alertState.initMaps()
+	return alertState
+}
+
+// maps may be missing from alert state restored from a state file
+func (a *ValidatorAlertState) initMaps() {
+	if a.AlertTypeCounts == nil {
+		a.AlertTypeCounts = make(map[AlertType]int64)
+	}
+	if a.SentryGRPCErrorCounts == nil {
+		a.SentryGRPCErrorCounts = make(map[string]int64)
+	}
+	if a.SentryOutOfSyncErrorCounts == nil {
+		a.SentryOutOfSyncErrorCounts = make(map[string]int64)
+	}
+	if a.SentryHaltErrorCounts == nil {
+		a.SentryHaltErrorCounts = make(map[string]int64)
+	}
+	if a.SentryCatchingUpErrorCounts == nil {
+		a.SentryCatchingUpErrorCounts = make(map[string]int64)
+	}
+	if a.SentryLowPeersErrorCounts == nil {
+		a.SentryLowPeersErrorCounts = make(map[string]int64)
+	}
+	if a.SentrySyncInfoErrorCounts == nil {
+		a.SentrySyncInfoErrorCounts = make(map[string]int64)
+	}
+	if a.SentryLatestHeight == nil {
+		a.SentryLatestHeight = make(map[string]int64)
+	}
+	if a.SentryVersions == nil {
+		a.SentryVersions = make(map[string]string)
+	}
+	if a.SentryNodeIDs == nil {
+		a.SentryNodeIDs = make(map[string]string)
+	}
+	if a.ReportedEvidence == nil {
+		a.ReportedEvidence = make(map[string]int64)
+	}
+	if a.GovVoteAlertLevels == nil {
+		a.GovVoteAlertLevels = make(map[uint64]AlertLevel)
+	}
+	if a.AlertKeyLevels == nil {
+		a.AlertKeyLevels = make(map[string]AlertLevel)
+	}
+}
+
+// drop counts for sentries that are no longer configured for the validator
+func (a *ValidatorAlertState) pruneSentries(vm *ValidatorMonitor) {
+	configured := make(map[string]bool)
+	if vm.Sentries != nil {
+		for _, sentry := range *vm.Sentries {
+			configured[sentry.Name] = true
+		}
+	}
+	for _, counts := range []map[string]int64{
+		a.SentryGRPCErrorCounts,
+		a.SentryOutOfSyncErrorCounts,
+		a.SentryHaltErrorCounts,
+		a.SentryCatchingUpErrorCounts,
+		a.SentryLowPeersErrorCounts,
+		a.SentrySyncInfoErrorCounts,
+		a.SentryLatestHeight,
+	} {
+		for sentryName := range counts {
+			if !configured[sentryName] {
+				delete(counts, sentryName)
+			}
+		}
+	}
+	for _, sentryStrings := range []map[string]string{
+		a.SentryVersions,
+		a.SentryNodeIDs,
+	} {
+		for sentryName := range sentryStrings {
+			if !configured[sentryName] {
+				delete(sentryStrings, sentryName)
+			}
+		}
+	}
+}
+
+func copyAlertTypeCounts(m map[AlertType]int64) map[AlertType]int64 {
+	c := make(map[AlertType]int64, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func copyStringCounts(m map[string]int64) map[string]int64 {
+	c := make(map[string]int64, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func copySentryStrings(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func copyGovVoteAlertLevels(m map[uint64]AlertLevel) map[uint64]AlertLevel {
+	c := make(map[uint64]AlertLevel, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func copyAlertKeyLevels(m map[string]AlertLevel) map[string]AlertLevel {
+	c := make(map[string]AlertLevel, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// requires locked alertState
+func (a *ValidatorAlertState) snapshot() *ValidatorAlertState {
+	snapshot := *a
+	snapshot.AlertTypeCounts = copyAlertTypeCounts(a.AlertTypeCounts)
+	snapshot.SentryGRPCErrorCounts = copyStringCounts(a.SentryGRPCErrorCounts)
+	snapshot.SentryOutOfSyncErrorCounts = copyStringCounts(a.SentryOutOfSyncErrorCounts)
+	snapshot.SentryHaltErrorCounts = copyStringCounts(a.SentryHaltErrorCounts)
+	snapshot.SentryCatchingUpErrorCounts = copyStringCounts(a.SentryCatchingUpErrorCounts)
+	snapshot.SentryLowPeersErrorCounts = copyStringCounts(a.SentryLowPeersErrorCounts)
+	snapshot.SentrySyncInfoErrorCounts = copyStringCounts(a.SentrySyncInfoErrorCounts)
+	snapshot.SentryLatestHeight = copyStringCounts(a.SentryLatestHeight)
+	snapshot.ReportedEvidence = copyStringCounts(a.ReportedEvidence)
+	snapshot.SentryVersions = copySentryStrings(a.SentryVersions)
+	snapshot.SentryNodeIDs = copySentryStrings(a.SentryNodeIDs)
+	snapshot.GovVoteAlertLevels = copyGovVoteAlertLevels(a.GovVoteAlertLevels)
+	snapshot.AlertKeyLevels = copyAlertKeyLevels(a.AlertKeyLevels)
+	return &snapshot
+}
+
+// loads the state file, starting with empty state if it does not exist yet
+func loadStateStore(file string) (*StateStore, error) {
+	store := &StateStore{
+		file:  file,
+		state: HalfLifeState{Validators: make(map[string]*ValidatorState)},
+	}
+	dat, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return store, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(dat, &store.state); err != nil {
+		return nil, fmt.Errorf("error parsing state file %s: %w", file, err)
+	}
+	if store.state.Validators == nil {
+		store.state.Validators = make(map[string]*ValidatorState)
+	}
+	return store, nil
+}
+
+// restored alert state for the validator, or empty alert state if there is none
+func (s *StateStore) alertState(validator string) *ValidatorAlertState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	validatorState, ok := s.state.Validators[validator]
+	if !ok || validatorState.AlertState == nil {
+		return newValidatorAlertState()
+	}
+	alertState := validatorState.AlertState.snapshot()
+	alertState.initMaps()
+	return alertState
+}
+
+func (s *StateStore) validatorState(validator string) *ValidatorState {
+	validatorState, ok := s.state.Validators[validator]
+	if !ok {
+		validatorState = &ValidatorState{}
+		s.state.Validators[validator] = validatorState
+	}
+	return validatorState
+}
+
+// snapshot must not be modified after it is saved
+func (s *StateStore) saveAlertState(validator string, snapshot *ValidatorAlertState) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.validatorState(validator).AlertState = snapshot
+	if err := s.write(); err != nil {
+		fmt.Printf("Error saving state file %v\n", err)
+	}
+}
+
+// requires locked store. Writes to a temporary file that replaces the state file,
+// so the state file is never left partially written.
+func (s *StateStore) write() error {
+	dat, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(s.file), fmt.Sprintf(".%s-*", filepath.Base(s.file)))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(dat); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpFile.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), s.file)
+}
+
+func (s *StateStore) statusMessageID(validator string, sinkName string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	validatorState, ok := s.state.Validators[validator]
+	if !ok {
+		return "", false
+	}
+	messageID, ok := validatorState.StatusMessageIDs[sinkName]
+	return messageID, ok
+}
+
+func (s *StateStore) saveStatusMessageID(validator string, sinkName string, messageID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.setStatusMessageID(validator, sinkName, messageID)
+	if err := s.write(); err != nil {
+		fmt.Printf("Error saving state file %v\n", err)
+	}
+}
+
+// requires locked store
+func (s *StateStore) setStatusMessageID(validator string, sinkName string, messageID string) {
+	validatorState := s.validatorState(validator)
+	if validatorState.StatusMessageIDs == nil {
+		validatorState.StatusMessageIDs = make(map[string]string)
+	}
+	validatorState.StatusMessageIDs[sinkName] = messageID
+}
+
+// drops the state of validators that are no longer configured
+func (s *StateStore) pruneValidators(config *HalfLifeConfig) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	configured := make(map[string]bool)
+	for _, vm := range config.Validators {
+		configured[vm.Name] = true
+	}
+	pruned := false
+	for name := range s.state.Validators {
+		if !configured[name] {
+			delete(s.state.Validators, name)
+			pruned = true
+		}
+	}
+	if !pruned {
+		return
+	}
+	if err := s.write(); err != nil {
+		fmt.Printf("Error saving state file %v\n", err)
+	}
+}
+
+// Status message IDs used to be saved into config.yaml for each service. Takes any that are not in the state file yet
+// for the first sink of that service the validator is sent to. config.yaml is not modified, and any IDs left there are
+// ignored once they are in the state file.
+func (s *StateStore) migrateStatusMessageIDs(config *HalfLifeConfig) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	migrated := false
+	migrate := func(vm *ValidatorMonitor, service string, messageID *string) {
+		if messageID == nil {
+			return
+		}
+		for _, sink := range config.Notifications {
+			if sink.Service != service || !sink.matches(vm) {
+				continue
+			}
+			if validatorState, ok := s.state.Validators[vm.Name]; ok {
+				if _, ok := validatorState.StatusMessageIDs[sink.Name]; ok {
+					return
+				}
+			}
+			s.setStatusMessageID(vm.Name, sink.Name, *messageID)
+			fmt.Printf("Migrated status message ID for %s (%s) from config to state file\n", vm.Name, sink.Name)
+			migrated = true
+			return
+		}
+	}
+	for _, vm := range config.Validators {
+		migrate(vm, "discord", vm.DiscordStatusMessageID)
+		migrate(vm, "slack", vm.SlackStatusMessageTS)
+	}
+	if !migrated {
+		return
+	}
+	if err := s.write(); err != nil {
+		fmt.Printf("Error saving state file %v\n", err)
+	}
+}
@@ -0,0 +1,345 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// statusFormat holds the markup that differs between the chat services a validator status is rendered for
+type statusFormat struct {
+	bold      func(s string) string
+	timestamp func(t time.Time) string
+}
+
+func getAlertTitle(stats ValidatorStats, vm *ValidatorMonitor) string {
+	if stats.fullNode(vm) {
+		return vm.Name
+	}
+	if stats.SlashingPeriodUptime > 0 {
+		return fmt.Sprintf("%s (%.02f%% up)", vm.Name, stats.SlashingPeriodUptime)
+	}
+	return fmt.Sprintf("%s (N/A%% up)", vm.Name)
+}
+
+func getCurrentStatsTitleAndDescription(stats ValidatorStats, vm *ValidatorMonitor, f statusFormat) (title string, description string) {
+	var uptime string
+	if stats.fullNode(vm) {
+		title = vm.Name
+	} else {
+		if stats.SlashingPeriodUptime == 0 {
+			uptime = "N/A"
+		} else {
+			uptime = fmt.Sprintf("%.02f", stats.SlashingPeriodUptime)
+		}
+
+		title = fmt.Sprintf("%s (%s%% up)", vm.Name, uptime)
+	}
+
+	sentryString := ""
+
+	if vm.Sentries != nil {
+		for _, vmSentry := range *vm.Sentries {
+			sentryFound := false
+			for _, sentryStats := range stats.SentryStats {
+				if vmSentry.Name == sentryStats.Name {
+					var statusIcon string
+					if sentryStats.SentryAlertType == sentryAlertTypeNone {
+						statusIcon = iconGood
+					} else {
+						statusIcon = iconError
+					}
+
+					var height string
+					if sentryStats.Height == 0 {
+						height = "N/A"
+					} else {
+						height = fmt.Sprint(sentryStats.Height)
+					}
+					var version string
+					if sentryStats.Version == "" {
+						version = "N/A"
+					} else {
+						version = sentryStats.Version
+						if vm.SentryMinVersion != "" && compareVersions(sentryStats.Version, vm.SentryMinVersion) < 0 {
+							if statusIcon == iconGood {
+								statusIcon = iconWarning
+							}
+							version += fmt.Sprintf(" (below minimum %s)", vm.SentryMinVersion)
+						}
+					}
+
+					var sync string
+					if sentryStats.CatchingUp {
+						sync = " - " + f.bold("Catching Up")
+					}
+					if sentryStats.PeersChecked {
+						sync += fmt.Sprintf(" - Peers %s", f.bold(fmt.Sprint(sentryStats.Peers)))
+					}
+
+					sentryString += fmt.Sprintf("\n%s %s - Height %s - Version %s%s", statusIcon, f.bold(sentryStats.Name), f.bold(height), f.bold(version), sync)
+					sentryFound = true
+					break
+				}
+			}
+			if !sentryFound {
+				sentryString += fmt.Sprintf("\n%s %s - Height %s - Version %s", iconError, f.bold(vmSentry.Name), f.bold("N/A"), f.bold("N/A"))
+			}
+		}
+	}
+
+	recentSignedBlocks := fmt.Sprintf("%s Latest Blocks Signed: %s", iconWarning, f.bold("N/A"))
+
+	var latestBlock string
+	if stats.Timestamp.Before(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		latestBlock = fmt.Sprintf("%s Height %s", iconError, f.bold("N/A"))
+	} else {
+		var rpcStatusIcon string
+		if stats.RPCError {
+			rpcStatusIcon = iconError
+		} else {
+			rpcStatusIcon = iconGood
+			if !stats.fullNode(vm) {
+				var recentSignedBlocksIcon string
+				switch level := stats.RecentMissedBlockAlertLevel; {
+				case level >= alertLevelHigh:
+					recentSignedBlocksIcon = iconError
+				case level == alertLevelWarning || stats.RecentNilVotes > 0:
+					recentSignedBlocksIcon = iconWarning
+				default:
+					recentSignedBlocksIcon = iconGood
+				}
+				recentSignedBlocks = fmt.Sprintf("%s Latest Blocks Signed: %s", recentSignedBlocksIcon, f.bold(fmt.Sprintf("%d/%d", vm.RecentBlocksToCheck-stats.RecentMissedBlocks-stats.RecentNilVotes, vm.RecentBlocksToCheck)))
+				if stats.RecentMissedBlocks > 0 || stats.RecentNilVotes > 0 {
+					if stats.RecentNetworkMissedBlocks > 0 {
+						recentSignedBlocks += fmt.Sprintf(" (%s nil, %s absent, %s network-wide)", f.bold(fmt.Sprint(stats.RecentNilVotes)), f.bold(fmt.Sprint(stats.RecentMissedBlocks)), f.bold(fmt.Sprint(stats.RecentNetworkMissedBlocks)))
+					} else {
+						recentSignedBlocks += fmt.Sprintf(" (%s nil, %s absent)", f.bold(fmt.Sprint(stats.RecentNilVotes)), f.bold(fmt.Sprint(stats.RecentMissedBlocks)))
+					}
+				}
+			}
+		}
+		latestBlock = fmt.Sprintf("%s Height %s - %s", rpcStatusIcon, f.bold(fmt.Sprint(stats.Height)), f.bold(f.timestamp(stats.Timestamp)))
+	}
+
+	activeSet := ""
+	if !vm.FullNode && stats.ActiveSetChecked {
+		if stats.Inactive {
+			activeSet = fmt.Sprintf("\n%s Active Set: %s", iconWarning, f.bold("Inactive"))
+		} else {
+			activeSet = fmt.Sprintf("\n%s Active Set: Rank %s/%d - Voting Power %s", iconGood, f.bold(fmt.Sprint(stats.ActiveSetRank)), stats.ActiveSetSize, f.bold(fmt.Sprint(stats.VotingPower)))
+		}
+	}
+
+	// validator details shown after the latest block
+	details := getRPCEndpointsStatus(stats, f) + getConsensusStatus(stats, vm, f) + getBlockHashStatus(stats, f) + getParticipationStatus(stats, vm, f) + getSignatureLatencyStatus(stats, vm, f) + getProposalsStatus(stats, vm, f) + getTopologyStatus(stats, f) + activeSet + getJailMarginStatus(stats, f) + getUpgradeStatus(stats, f) + getGovernanceStatus(stats, f)
+
+	if stats.fullNode(vm) {
+		description = fmt.Sprintf("%s%s%s", latestBlock, details, sentryString)
+	} else {
+		if stats.Height == stats.LastSignedBlockHeight {
+			description = fmt.Sprintf("%s%s\n%s%s",
+				latestBlock, details, recentSignedBlocks, sentryString)
+		} else {
+			var lastSignedBlock string
+			if stats.LastSignedBlockHeight == -1 {
+				lastSignedBlock = fmt.Sprintf("%s Last Signed %s", iconError, f.bold("N/A"))
+			} else {
+				lastSignedBlock = fmt.Sprintf("%s Last Signed %s - %s", iconError, f.bold(fmt.Sprint(stats.LastSignedBlockHeight)), f.bold(f.timestamp(stats.LastSignedBlockTimestamp)))
+			}
+			description = fmt.Sprintf("%s%s\n%s\n%s%s",
+				latestBlock, details, lastSignedBlock, recentSignedBlocks, sentryString)
+		}
+	}
+
+	return
+}
+
+// proposals in their voting period, with the proposals that the validator has not voted on
+func getGovernanceStatus(stats ValidatorStats, f statusFormat) string {
+	if !stats.GovChecked {
+		return ""
+	}
+	notVoted := ""
+	notVotedCount := 0
+	for _, proposal := range stats.GovProposals {
+		if proposal.Voted {
+			continue
+		}
+		notVotedCount++
+		icon := iconWarning
+		if getGovVoteAlertLevel(proposal.VotingEndTime) >= alertLevelHigh {
+			icon = iconError
+		}
+		notVoted += fmt.Sprintf("\n%s Proposal %s not voted - ends in %s", icon, f.bold(fmt.Sprintf("#%d", proposal.ID)), f.bold(formatDurationHours(time.Until(proposal.VotingEndTime))))
+	}
+	icon := iconGood
+	if notVotedCount > 0 {
+		icon = iconWarning
+	}
+	return fmt.Sprintf("\n%s Governance: %s in voting period, %s not voted%s", icon, f.bold(fmt.Sprint(len(stats.GovProposals))), f.bold(fmt.Sprint(notVotedCount)), notVoted)
+}
+
+// planned upgrade with the estimated upgrade time
+func getUpgradeStatus(stats ValidatorStats, f statusFormat) string {
+	if stats.UpgradePlan == nil {
+		return ""
+	}
+	plan := stats.UpgradePlan
+	icon := iconGood
+	if stats.Height >= plan.Height-1 || getUpgradeReminder(plan.EstimatedTime) == upgradeReminderHour {
+		icon = iconWarning
+	}
+	estimate := ""
+	if !plan.EstimatedTime.IsZero() && plan.Height > stats.Height {
+		estimate = fmt.Sprintf(" - Estimated %s", f.bold(f.timestamp(plan.EstimatedTime)))
+	}
+	return fmt.Sprintf("\n%s Upgrade %s at Height %s%s", icon, f.bold(plan.Name), f.bold(fmt.Sprint(plan.Height)), estimate)
+}
+
+// missed blocks that are left before jailing, with the estimated time until jailing if every block is missed
+func getJailMarginStatus(stats ValidatorStats, f statusFormat) string {
+	if !stats.JailMarginChecked {
+		return ""
+	}
+	var icon string
+	switch getJailMarginAlertLevel(stats.JailMarginBlocks, stats.JailMarginMaxMissed) {
+	case alertLevelNone:
+		icon = iconGood
+	case alertLevelWarning:
+		icon = iconWarning
+	default:
+		icon = iconError
+	}
+	estimate := ""
+	if duration := stats.jailMarginDuration(); duration > 0 {
+		estimate = fmt.Sprintf(" (~%s)", formatDurationHours(duration))
+	}
+	return fmt.Sprintf("\n%s Jail Margin: %s missed blocks%s", icon, f.bold(fmt.Sprintf("%d/%d", stats.JailMarginBlocks, stats.JailMarginMaxMissed)), estimate)
+}
+
+// health of each rpc endpoint, when there are multiple
+func getRPCEndpointsStatus(stats ValidatorStats, f statusFormat) string {
+	rpcEndpoints := ""
+	for _, endpointStats := range stats.RPCEndpoints {
+		var icon, height string
+		switch {
+		case endpointStats.Error:
+			icon = iconError
+			height = "N/A"
+		case endpointStats.CatchingUp || stats.Height-endpointStats.Height > outOfSyncThreshold:
+			icon = iconWarning
+			height = fmt.Sprint(endpointStats.Height)
+		default:
+			icon = iconGood
+			height = fmt.Sprint(endpointStats.Height)
+		}
+		inUse := ""
+		if endpointStats.Current {
+			inUse = " (in use)"
+		}
+		rpcEndpoints += fmt.Sprintf("\n%s RPC %s - Height %s%s", icon, f.bold(endpointStats.Name), f.bold(height), inUse)
+	}
+	return rpcEndpoints
+}
+
+// validator node connections to its sentries, when the node rpc is configured
+func getTopologyStatus(stats ValidatorStats, f statusFormat) string {
+	if stats.Topology == nil {
+		return ""
+	}
+	icon := iconGood
+	if len(stats.Topology.DisconnectedSentries) > 0 {
+		icon = iconWarning
+		if len(stats.Topology.ConnectedSentries) == 0 {
+			icon = iconError
+		}
+	}
+	if len(stats.Topology.UnexpectedPeers) > 0 {
+		icon = iconError
+	}
+	sentries := len(stats.Topology.ConnectedSentries) + len(stats.Topology.DisconnectedSentries)
+	topology := fmt.Sprintf("\n%s Sentry Connections: %s sentries, %s peers", icon,
+		f.bold(fmt.Sprintf("%d/%d", len(stats.Topology.ConnectedSentries), sentries)), f.bold(fmt.Sprint(stats.Topology.Peers)))
+	if len(stats.Topology.UnexpectedPeers) > 0 {
+		topology += fmt.Sprintf(" (%s unexpected)", f.bold(fmt.Sprint(len(stats.Topology.UnexpectedPeers))))
+	}
+	return topology
+}
+
+// block hash comparison between the rpc node and sentries
+func getBlockHashStatus(stats ValidatorStats, f statusFormat) string {
+	if stats.BlockHashes == nil || len(stats.BlockHashes.Hashes) < 2 {
+		return ""
+	}
+	if stats.BlockHashes.Diverged {
+		return fmt.Sprintf("\n%s Block Hash: %s at height %s", iconError, f.bold("diverged"), f.bold(fmt.Sprint(stats.BlockHashes.Height)))
+	}
+	return fmt.Sprintf("\n%s Block Hash: matches across %s nodes at height %s", iconGood, f.bold(fmt.Sprint(len(stats.BlockHashes.Hashes))), f.bold(fmt.Sprint(stats.BlockHashes.Height)))
+}
+
+// voting power that signed the recent blocks checked
+func getParticipationStatus(stats ValidatorStats, vm *ValidatorMonitor, f statusFormat) string {
+	if stats.fullNode(vm) || !stats.ParticipationChecked {
+		return ""
+	}
+	icon := iconGood
+	if stats.MinParticipation < vm.participationThreshold() {
+		icon = iconError
+	} else if stats.MinParticipation < networkWideMissParticipation {
+		icon = iconWarning
+	}
+	return fmt.Sprintf("\n%s Participation: %s average, %s lowest at height %s", icon,
+		f.bold(fmt.Sprintf("%.1f%%", stats.AverageParticipation)), f.bold(fmt.Sprintf("%.1f%%", stats.MinParticipation)), f.bold(fmt.Sprint(stats.MinParticipationHeight)))
+}
+
+// how far behind the block time the validator's precommits were signed, compared to the rest of the set
+func getSignatureLatencyStatus(stats ValidatorStats, vm *ValidatorMonitor, f statusFormat) string {
+	l := stats.SignatureLatency
+	if stats.fullNode(vm) || l == nil {
+		return ""
+	}
+	icon := iconGood
+	if l.degraded(vm.signatureLatencyThreshold()) {
+		icon = iconWarning
+	}
+	return fmt.Sprintf("\n%s Signing Latency: p50 %s, p90 %s, p99 %s (set p50 %s, p90 %s)", icon,
+		f.bold(l.P50.Round(time.Millisecond).String()), f.bold(l.P90.Round(time.Millisecond).String()), f.bold(l.P99.Round(time.Millisecond).String()),
+		l.SetP50.Round(time.Millisecond), l.SetP90.Round(time.Millisecond))
+}
+
+// blocks proposed in the recent blocks checked against the validator's share of voting power
+func getProposalsStatus(stats ValidatorStats, vm *ValidatorMonitor, f statusFormat) string {
+	p := stats.Proposals
+	if stats.fullNode(vm) || p == nil {
+		return ""
+	}
+	icon := iconGood
+	if int64(len(p.Missed)) > vm.missedProposalsThreshold() {
+		icon = iconWarning
+	}
+	proposals := fmt.Sprintf("\n%s Proposals: %s", icon, f.bold(fmt.Sprint(p.Proposed)))
+	if p.Expected > 0 {
+		proposals += fmt.Sprintf(" of %s expected", f.bold(fmt.Sprintf("%.1f", p.Expected)))
+	}
+	if len(p.Missed) > 0 {
+		latest := p.Missed[0]
+		proposals += fmt.Sprintf(" - %s missed, latest at height %s round %s", f.bold(fmt.Sprint(len(p.Missed))), f.bold(fmt.Sprint(latest.Height)), f.bold(fmt.Sprint(latest.Round)))
+	}
+	return proposals
+}
+
+func getConsensusStatus(stats ValidatorStats, vm *ValidatorMonitor, f statusFormat) string {
+	c := stats.Consensus
+	if c == nil {
+		return ""
+	}
+	icon := iconGood
+	if c.StuckFor > consensusStuckThreshold || c.Round > vm.consensusRoundThreshold() {
+		icon = iconError
+	} else if c.Round > 0 {
+		icon = iconWarning
+	}
+	return fmt.Sprintf("\n%s Consensus: Height %s Round %s Step %s (%s prevotes, %s precommits)", icon,
+		f.bold(fmt.Sprint(c.Height)), f.bold(fmt.Sprint(c.Round)), f.bold(c.Step),
+		f.bold(fmt.Sprintf("%.0f%%", c.Prevotes)), f.bold(fmt.Sprintf("%.0f%%", c.Precommits)))
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// peers of the validator node compared against its sentries
+type TopologyStats struct {
+	Peers                int
+	ConnectedSentries    []string
+	DisconnectedSentries []string // sentries with a known node id that the validator is not connected to
+	UnexpectedPeers      []string // peers that are not sentries or allowed peers
+}
+
+type nodePeer struct {
+	ID       string
+	Moniker  string
+	RemoteIP string
+}
+
+func (p nodePeer) String() string {
+	return fmt.Sprintf("%s (%s@%s)", p.Moniker, p.ID, p.RemoteIP)
+}
+
+// connected peers of the validator node from its private rpc /net_info
+func getNodePeers(node Endpoint) ([]nodePeer, error) {
+	client, err := newClient(node)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+	defer cancel()
+	netInfo, err := client.NetInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	peers := make([]nodePeer, len(netInfo.Peers))
+	for i, peer := range netInfo.Peers {
+		peers[i] = nodePeer{
+			ID:       string(peer.NodeInfo.DefaultNodeID),
+			Moniker:  peer.NodeInfo.Moniker,
+			RemoteIP: peer.RemoteIP,
+		}
+	}
+	return peers, nil
+}
+
+// Compares the validator node peers against the node ids of the sentries, the latest known node id is used for sentries that could not be reached.
+// Unexpected peers are only known once every sentry has been reached.
+func getTopologyErrors(vm *ValidatorMonitor, stats *ValidatorStats, peers []nodePeer, sentryNodeIDs map[string]string, alertConfig AlertConfig) (errs []error) {
+	peerIDs := make([]string, len(peers))
+	for i, peer := range peers {
+		peerIDs[i] = peer.ID
+	}
+	topology := &TopologyStats{Peers: len(peers)}
+	var knownSentryIDs []string
+	allSentriesKnown := true
+	if vm.Sentries != nil {
+		for _, sentry := range *vm.Sentries {
+			nodeID, ok := sentryNodeIDs[sentry.Name]
+			if !ok {
+				allSentriesKnown = false
+				continue
+			}
+			knownSentryIDs = append(knownSentryIDs, nodeID)
+			if containsString(peerIDs, nodeID) {
+				topology.ConnectedSentries = append(topology.ConnectedSentries, sentry.Name)
+			} else {
+				topology.DisconnectedSentries = append(topology.DisconnectedSentries, sentry.Name)
+			}
+		}
+	}
+	for _, peer := range peers {
+		if allSentriesKnown && !containsString(knownSentryIDs, peer.ID) && !containsString(vm.AllowedPeers, peer.ID) {
+			topology.UnexpectedPeers = append(topology.UnexpectedPeers, peer.String())
+		}
+	}
+	sort.Strings(topology.UnexpectedPeers)
+	stats.Topology = topology
+
+	if len(topology.DisconnectedSentries) > 0 {
+		if err := newSentryConnectionsError(topology.DisconnectedSentries, len(topology.ConnectedSentries) == 0); err.Active(alertConfig) {
+			stats.increaseAlertLevel(err.alertLevel())
+			errs = append(errs, err)
+		}
+	}
+	if len(topology.UnexpectedPeers) > 0 {
+		if err := newUnexpectedPeersError(topology.UnexpectedPeers); err.Active(alertConfig) {
+			stats.increaseAlertLevel(alertLevelHigh)
+			errs = append(errs, err)
+		}
+	}
+	return
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cosmosClient "github.com/cosmos/cosmos-sdk/client"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+)
+
+const (
+	upgradeBlockTimeSampleBlocks   = 1000 // number of blocks to average the block time over to estimate the upgrade time
+	upgradeBlockTimeResampleBlocks = 100  // blocks produced before the average block time is sampled again
+
+	// reminders are sent when the upgrade is first seen and then as the estimated upgrade time approaches
+	upgradeReminderScheduled = 1
+	upgradeReminderDay       = 2
+	upgradeReminderHour      = 3
+)
+
+type UpgradePlanStats struct {
+	Name          string
+	Height        int64
+	EstimatedTime time.Time // zero if the block time could not be determined
+}
+
+func upgradeAlertKey(name string) string {
+	return fmt.Sprintf("upgrade-%s", name)
+}
+
+func getUpgradeReminder(estimatedTime time.Time) int {
+	if estimatedTime.IsZero() {
+		return upgradeReminderScheduled
+	}
+	switch remaining := time.Until(estimatedTime); {
+	case remaining <= time.Hour:
+		return upgradeReminderHour
+	case remaining <= 24*time.Hour:
+		return upgradeReminderDay
+	default:
+		return upgradeReminderScheduled
+	}
+}
+
+func getUpgradeReminderAlertLevel(reminder int) AlertLevel {
+	if reminder == upgradeReminderHour {
+		return alertLevelWarning
+	}
+	return alertLevelNone
+}
+
+// whether a node at height is halted for the upgrade. Nodes stop before committing the block at the upgrade height.
+func (a *ValidatorAlertState) isUpgradeHeight(height int64) bool {
+	return a.UpgradePlanName != "" && height >= a.UpgradePlanHeight-1
+}
+
+// Average block time of recent blocks. The sample is kept with the window so the block it is measured from is only fetched
+// again once upgradeBlockTimeResampleBlocks more blocks have been produced. 0 if there are not enough blocks yet.
+func (w *blockWindow) sampledAverageBlockTime(node rpcclient.Client, stats *ValidatorStats) (time.Duration, error) {
+	w.mutex.Lock()
+	if w.sampledBlockTime > 0 && stats.Height >= w.sampledBlockTimeHeight && stats.Height-w.sampledBlockTimeHeight < upgradeBlockTimeResampleBlocks {
+		defer w.mutex.Unlock()
+		return w.sampledBlockTime, nil
+	}
+	w.mutex.Unlock()
+	sampleHeight := stats.Height - upgradeBlockTimeSampleBlocks
+	if sampleHeight < 1 {
+		sampleHeight = 1
+	}
+	if sampleHeight >= stats.Height {
+		return 0, nil
+	}
+	blockCtx, blockCtxCancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
+	block, err := node.Block(blockCtx, &sampleHeight)
+	blockCtxCancel()
+	if err != nil {
+		return 0, err
+	}
+	averageBlockTime := stats.Timestamp.Sub(block.Block.Time) / time.Duration(stats.Height-sampleHeight)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.sampledBlockTime = averageBlockTime
+	w.sampledBlockTimeHeight = stats.Height
+	return averageBlockTime, nil
+}
+
+// estimates when the block at height will be committed from the average block time of recent blocks
+func estimateBlockTime(node rpcclient.Client, blocks *blockWindow, stats *ValidatorStats, height int64) (time.Time, error) {
+	averageBlockTime, err := blocks.sampledAverageBlockTime(node, stats)
+	if err != nil || averageBlockTime == 0 {
+		return time.Time{}, err
+	}
+	return stats.Timestamp.Add(averageBlockTime * time.Duration(height-stats.Height)), nil
+}
+
+// Checks for a planned software upgrade, reminders are sent from the plan in stats. Requires the latest height and timestamp in stats.
+func monitorUpgradePlan(client *cosmosClient.Context, node rpcclient.Client, blocks *blockWindow, stats *ValidatorStats) (errs []IgnorableError) {
+	stats.UpgradeChecked = false
+	stats.UpgradePlan = nil
+	plan, err := getUpgradePlan(client)
+	if err != nil {
+		errs = append(errs, newGenericRPCError(err.Error()))
+		return
+	}
+	stats.UpgradeChecked = true
+	if plan.Plan == nil {
+		return
+	}
+	stats.UpgradePlan = &UpgradePlanStats{Name: plan.Plan.Name, Height: plan.Plan.Height}
+	if stats.Height > 0 && plan.Plan.Height > stats.Height {
+		estimatedTime, err := estimateBlockTime(node, blocks, stats, plan.Plan.Height)
+		if err != nil {
+			// estimate is only informational, so this is not an alert
+			fmt.Printf("Error estimating upgrade time: %v\n", err)
+		}
+		stats.UpgradePlan.EstimatedTime = estimatedTime
+	}
+	return
+}
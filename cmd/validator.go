@@ -9,6 +9,7 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/types/bech32"
 	"github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/types"
 )
 
 const (
@@ -21,23 +22,42 @@ const (
 func monitorValidator(
 	config *HalfLifeConfig,
 	vm *ValidatorMonitor,
+	rpc Endpoint,
 	stats *ValidatorStats,
+	blocks *blockWindow,
 ) (errs []IgnorableError) {
 	stats.LastSignedBlockHeight = -1
+	stats.ActiveSetChecked = false
+	stats.Inactive = false
+	stats.JailMarginChecked = false
 	fmt.Printf("Monitoring validator: %s\n", vm.Name)
-	client, err := getCosmosClient(vm.RPC, vm.ChainID)
+	client, err := getCosmosClient(rpc, vm.ChainID)
+	if err != nil {
+		errs = append(errs, newGenericRPCError(err.Error()))
+		return
+	}
+	node, err := client.GetNode()
 	if err != nil {
 		errs = append(errs, newGenericRPCError(err.Error()))
 		return
 	}
 	slashingPeriod := int64(10000)
 	var hexAddress []byte
+	var participation *participationSet
 	if !vm.FullNode {
 		_, hexAddress, err = bech32.DecodeAndConvert(vm.Address)
 		if err != nil {
 			errs = append(errs, newIgnorableError(err))
 			return
 		}
+		validatorSet, err := getValidatorSet(node, nil)
+		if err != nil {
+			// membership is unknown, so continue to monitor signing
+			errs = append(errs, newGenericRPCError(err.Error()))
+		} else {
+			participation = newParticipationSet(validatorSet)
+			stats.setActiveSetMembership(validatorSet, hexAddress)
+		}
 		valInfo, err := getSigningInfo(client, vm.Address)
 		if err != nil {
 			errs = append(errs, newGenericRPCError(err.Error()))
@@ -56,16 +76,20 @@ func monitorValidator(
 				slashingPeriod = slashingInfo.Params.SignedBlocksWindow
 				stats.SlashingPeriodUptime = 100.0 - 100.0*(float64(signingInfo.MissedBlocksCounter)/float64(slashingPeriod))
 
-				if stats.SlashingPeriodUptime < vm.SlashingPeriodUptimeErrorThreshold {
+				// jailed validators have their missed blocks counter reset when they are unjailed, and inactive validators are not jailed for downtime
+				if !stats.Inactive && !signingInfo.JailedUntil.After(time.Now()) && !signingInfo.Tombstoned {
+					stats.setJailMargin(slashingInfo.Params, signingInfo)
+				}
+
+				// inactive validators are not expected to sign, so uptime will not recover until they are active again
+				if !stats.Inactive && stats.SlashingPeriodUptime < vm.SlashingPeriodUptimeErrorThreshold {
 					errs = append(errs, newSlashingSLAError(stats.SlashingPeriodUptime, vm.SlashingPeriodUptimeErrorThreshold))
 				}
 			}
 		}
-	}
-	node, err := client.GetNode()
-	if err != nil {
-		errs = append(errs, newGenericRPCError(err.Error()))
-		return
+		if vm.OperatorAddress != "" {
+			errs = append(errs, monitorGovernance(client, vm, stats)...)
+		}
 	}
 	statusCtx, statusCtxCancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
 	status, err := node.Status(statusCtx)
@@ -74,7 +98,7 @@ func monitorValidator(
 		errs = append(errs, newGenericRPCError(err.Error()))
 	} else {
 		if status.SyncInfo.CatchingUp {
-			errs = append(errs, newOutOfSyncError(vm.RPC))
+			errs = append(errs, newOutOfSyncError(rpc.URL))
 		} else {
 			timeSinceLastBlock := time.Now().UnixNano() - status.SyncInfo.LatestBlockTime.UnixNano()
 			if timeSinceLastBlock > haltThresholdNanoseconds {
@@ -83,33 +107,123 @@ func monitorValidator(
 		}
 		stats.Height = status.SyncInfo.LatestBlockHeight
 		stats.Timestamp = status.SyncInfo.LatestBlockTime
+		errs = append(errs, monitorUpgradePlan(client, node, blocks, stats)...)
 		stats.RecentMissedBlocks = 0
-		if !vm.FullNode {
+		stats.RecentMissedHeights = nil
+		stats.RecentNilVotes = 0
+		stats.RecentSignedBlocks = 0
+		stats.RecentNetworkMissedBlocks = 0
+		stats.ParticipationChecked = false
+		if !stats.fullNode(vm) {
+			var newestBlock, oldestBlock *blockRecord
+			var participationSum float64
+			var participationBlocks int64
+			var latencies signatureLatencies
+			proposals := &ProposalStats{}
 			for i := stats.Height; i > stats.Height-vm.RecentBlocksToCheck && i > 0; i-- {
-				blockCtx, blockCtxCancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
-				block, err := node.Block(blockCtx, &i)
-				blockCtxCancel()
+				block, err := blocks.block(node, i)
 				if err != nil {
 					// generic RPC error for this one so it will be included in the generic RPC error retry
-					errs = append(errs, newGenericRPCError(newBlockFetchError(i, vm.RPC).Error()))
+					errs = append(errs, newGenericRPCError(newBlockFetchError(i, rpc.URL).Error()))
 					continue
 				}
+				if newestBlock == nil {
+					newestBlock = block
+				}
+				if reflect.DeepEqual(block.ProposerAddress, bytes.HexBytes(hexAddress)) {
+					proposals.Proposed++
+				}
+				// the round this block was committed in is in the last commit of the newer block
+				if oldestBlock != nil && oldestBlock.Height == i+1 && oldestBlock.LastCommit.Round > 0 {
+					proposers, err := blocks.skippedProposers(node, i, oldestBlock.LastCommit.Round)
+					if err != nil {
+						errs = append(errs, newGenericRPCError(fmt.Sprintf("error fetching validator set for height %d from %s: %v", i, rpc.URL, err)))
+					} else {
+						proposals.addSkippedRounds(block, proposers, hexAddress)
+					}
+				}
+				oldestBlock = block
 				if i == 1 {
 					break
 				}
-				found := false
-				for _, voter := range block.Block.LastCommit.Signatures {
-					if reflect.DeepEqual(voter.ValidatorAddress, bytes.HexBytes(hexAddress)) {
-						if block.Block.Height > stats.LastSignedBlockHeight {
-							stats.LastSignedBlockHeight = block.Block.Height
-							stats.LastSignedBlockTimestamp = block.Block.Time
-						}
-						found = true
-						break
-					}
+				networkWide := false
+				if participation != nil {
+					all, others := participation.participation(block, hexAddress)
+					participationSum += all
+					participationBlocks++
+					stats.setMinParticipation(all, block.Height)
+					networkWide = others < networkWideMissParticipation
 				}
-				if !found {
+				// absent votes do not include the validator address, so a validator without a vote in the commit missed the block
+				switch getCommitVote(block, hexAddress) {
+				case types.BlockIDFlagCommit:
+					stats.RecentSignedBlocks++
+					latencies.add(block, hexAddress)
+					if block.Height > stats.LastSignedBlockHeight {
+						stats.LastSignedBlockHeight = block.Height
+						stats.LastSignedBlockTimestamp = block.Time
+					}
+				case types.BlockIDFlagNil:
+					stats.RecentNilVotes++
+				default:
 					stats.RecentMissedBlocks++
+					stats.RecentMissedHeights = append(stats.RecentMissedHeights, block.Height)
+					if networkWide {
+						stats.RecentNetworkMissedBlocks++
+					}
+				}
+				for _, evidence := range getDoubleSignEvidence(vm, block, hexAddress) {
+					errs = append(errs, newDoubleSignError(evidence))
+				}
+			}
+			stats.AverageBlockTime = getAverageBlockTime(newestBlock, oldestBlock)
+
+			if newestBlock != nil {
+				if participation != nil {
+					proposals.Expected = float64(newestBlock.Height-oldestBlock.Height+1) * participation.votingPowerShare(hexAddress)
+				}
+				stats.Proposals = proposals
+			}
+			if int64(len(proposals.Missed)) > vm.missedProposalsThreshold() {
+				errs = append(errs, newMissedProposalsError(proposals.Missed, vm.RecentBlocksToCheck))
+			}
+
+			stats.SignatureLatency = latencies.stats()
+			if stats.SignatureLatency != nil && stats.SignatureLatency.degraded(vm.signatureLatencyThreshold()) {
+				errs = append(errs, newSignatureLatencyError(stats.SignatureLatency))
+			}
+
+			if participationBlocks > 0 {
+				stats.AverageParticipation = participationSum / float64(participationBlocks)
+				if stats.MinParticipation < vm.participationThreshold() {
+					errs = append(errs, newLowParticipationError(stats.MinParticipation, stats.MinParticipationHeight, vm.participationThreshold()))
+				}
+			}
+
+			if stats.JailMarginChecked && getJailMarginAlertLevel(stats.JailMarginBlocks, stats.JailMarginMaxMissed) > alertLevelNone {
+				errs = append(errs, newJailMarginError(stats.JailMarginBlocks, stats.JailMarginMaxMissed, stats.jailMarginDuration()))
+			}
+
+			var nilVotesThreshold int64
+			if vm.NilVotesThreshold == nil {
+				nilVotesThreshold = defaultNilVotesThreshold
+			} else {
+				nilVotesThreshold = *vm.NilVotesThreshold
+			}
+			if stats.RecentNilVotes > nilVotesThreshold {
+				errs = append(errs, newNilVotesError(stats.RecentNilVotes, vm.RecentBlocksToCheck))
+			}
+		} else if len(hexAddress) > 0 {
+			// evidence can be committed after the validator has left the active set, so it is checked even when the validator is not signing.
+			// Full nodes have no address to match evidence against, evidence shared by signer labels is reported by the other validators.
+			for i := stats.Height; i > stats.Height-vm.RecentBlocksToCheck && i > 1; i-- {
+				block, err := blocks.block(node, i)
+				if err != nil {
+					errs = append(errs, newGenericRPCError(newBlockFetchError(i, rpc.URL).Error()))
+					continue
+				}
+				for _, evidence := range getDoubleSignEvidence(vm, block, hexAddress) {
+					errs = append(errs, newDoubleSignError(evidence))
 				}
 			}
 		}
@@ -121,39 +235,63 @@ func monitorValidator(
 			missedBlocksThreshold = *vm.MissedBlocksThreshold
 		}
 
-		if !vm.FullNode && stats.RecentMissedBlocks > missedBlocksThreshold {
-			errs = append(errs, newMissedRecentBlocksError(stats.RecentMissedBlocks, vm.RecentBlocksToCheck))
+		if !stats.fullNode(vm) && stats.RecentMissedBlocks > missedBlocksThreshold {
+			errs = append(errs, newMissedRecentBlocksError(stats.RecentMissedBlocks, vm.RecentBlocksToCheck, stats.RecentNetworkMissedBlocks))
 			// Go back to find last signed block
 			if stats.LastSignedBlockHeight == -1 {
 				for i := stats.Height - vm.RecentBlocksToCheck; stats.LastSignedBlockHeight == -1 && i > (stats.Height-slashingPeriod) && i > 0; i-- {
-					blockCtx, blockCtxCancel := context.WithTimeout(context.Background(), time.Duration(time.Second*RPCTimeoutSeconds))
-					block, err := node.Block(blockCtx, &i)
-					blockCtxCancel()
+					block, err := blocks.block(node, i)
 					if err != nil {
-						errs = append(errs, newBlockFetchError(i, vm.RPC))
+						errs = append(errs, newBlockFetchError(i, rpc.URL))
 						break
 					}
 					if i == 1 {
 						break
 					}
-					for _, voter := range block.Block.LastCommit.Signatures {
-						if reflect.DeepEqual(voter.ValidatorAddress, bytes.HexBytes(hexAddress)) {
-							stats.LastSignedBlockHeight = block.Block.Height
-							stats.LastSignedBlockTimestamp = block.Block.Time
-							break
-						}
-					}
-					if stats.LastSignedBlockHeight != -1 {
+					if getCommitVote(block, hexAddress) == types.BlockIDFlagCommit {
+						stats.LastSignedBlockHeight = block.Height
+						stats.LastSignedBlockTimestamp = block.Time
 						break
 					}
 				}
 			}
 		}
+
+		if !stats.fullNode(vm) {
+			errs = confirmVerdicts(vm, rpc, stats, missedBlocksThreshold, errs)
+		}
 	}
 
 	return
 }
 
+// sets whether the validator is in the active set, and its voting power and rank if it is
+func (stats *ValidatorStats) setActiveSetMembership(validatorSet []*types.Validator, hexAddress []byte) {
+	stats.ActiveSetChecked = true
+	stats.ActiveSetSize = len(validatorSet)
+	stats.Inactive = true
+	stats.VotingPower = 0
+	stats.ActiveSetRank = 0
+	for i, validator := range validatorSet {
+		if reflect.DeepEqual(validator.Address, bytes.HexBytes(hexAddress)) {
+			stats.Inactive = false
+			stats.VotingPower = validator.VotingPower
+			stats.ActiveSetRank = i + 1
+			return
+		}
+	}
+}
+
+// the validator's vote in the block's last commit, absent if the validator is not in the commit
+func getCommitVote(block *blockRecord, hexAddress []byte) types.BlockIDFlag {
+	for _, voter := range block.LastCommit.Signatures {
+		if reflect.DeepEqual(voter.ValidatorAddress, bytes.HexBytes(hexAddress)) {
+			return voter.BlockIDFlag
+		}
+	}
+	return types.BlockIDFlagAbsent
+}
+
 func monitorSentry(
 	wg *sync.WaitGroup,
 	errs *[]error,
@@ -164,26 +302,47 @@ func monitorSentry(
 	alertState *ValidatorAlertState,
 	alertStateLock *sync.Mutex,
 ) {
-	nodeInfo, syncInfo, err := getSentryInfo(sentry.GRPC)
 	var errsToAdd []error
 	sentryStats := SentryStats{Name: sentry.Name, SentryAlertType: sentryAlertTypeNone}
+	latestBlockTime, syncErr, err := getSentryStats(sentry, &sentryStats)
 	if err != nil {
 		errsToAdd = append(errsToAdd, newSentryGRPCError(sentry.Name, err.Error()))
 		sentryStats.SentryAlertType = sentryAlertTypeGRPCError
 	} else {
-		sentryStats.Height = syncInfo.Block.Header.Height
-		sentryStats.Version = nodeInfo.ApplicationVersion.GetVersion()
 		alertStateLock.Lock()
-		blockDelta := syncInfo.Block.Header.Height - alertState.SentryLatestHeight[sentry.Name]
-		alertState.SentryLatestHeight[sentry.Name] = syncInfo.Block.Header.Height
+		blockDelta := sentryStats.Height - alertState.SentryLatestHeight[sentry.Name]
+		alertState.SentryLatestHeight[sentry.Name] = sentryStats.Height
+		previousVersion := alertState.SentryVersions[sentry.Name]
+		alertState.SentryVersions[sentry.Name] = sentryStats.Version
+		if sentryStats.NodeID != "" {
+			alertState.SentryNodeIDs[sentry.Name] = sentryStats.NodeID
+		}
 		alertStateLock.Unlock()
-		if blockDelta == 0 {
-			timeSinceLastBlock := time.Now().UnixNano() - syncInfo.Block.Header.Time.UnixNano()
+		if previousVersion != "" && previousVersion != sentryStats.Version {
+			errsToAdd = append(errsToAdd, newSentryVersionChangedError(sentry.Name, previousVersion, sentryStats.Version))
+		}
+		if sentryStats.CatchingUp {
+			errsToAdd = append(errsToAdd, newSentryCatchingUpError(sentry.Name, sentryStats.Height))
+			sentryStats.SentryAlertType = sentryAlertTypeCatchingUp
+		} else if blockDelta == 0 {
+			timeSinceLastBlock := time.Now().UnixNano() - latestBlockTime.UnixNano()
 			if timeSinceLastBlock > haltThresholdNanoseconds {
-				errsToAdd = append(errsToAdd, newSentryHaltError(sentry.Name, timeSinceLastBlock))
+				errsToAdd = append(errsToAdd, newSentryHaltError(sentry.Name, sentryStats.Height, timeSinceLastBlock))
 				sentryStats.SentryAlertType = sentryAlertTypeHalt
 			}
 		}
+		if syncErr != nil {
+			errsToAdd = append(errsToAdd, newSentrySyncInfoError(sentry.Name, syncErr.Error()))
+			if sentryStats.SentryAlertType == sentryAlertTypeNone {
+				sentryStats.SentryAlertType = sentryAlertTypeSyncInfo
+			}
+		}
+		if sentryStats.PeersChecked && sentryStats.Peers < vm.sentryMinPeers() {
+			errsToAdd = append(errsToAdd, newSentryLowPeersError(sentry.Name, sentryStats.Peers, vm.sentryMinPeers()))
+			if sentryStats.SentryAlertType == sentryAlertTypeNone {
+				sentryStats.SentryAlertType = sentryAlertTypeLowPeers
+			}
+		}
 	}
 	errsLock.Lock()
 	stats.SentryStats = append(stats.SentryStats, &sentryStats)
@@ -210,16 +369,18 @@ func monitorSentries(
 	return errs
 }
 
-func runMonitor(
-	notificationService NotificationService,
-	alertState *ValidatorAlertState,
-	alertStateLock *sync.Mutex,
-	configFile string,
-	config *HalfLifeConfig,
-	vm *ValidatorMonitor,
-	writeConfigMutex *sync.Mutex,
-) {
+func runMonitor(monitor *Monitor, runner *validatorRunner) {
+	alertState := runner.alertState
+	alertStateLock := &runner.alertStateLock
+	stateStore := monitor.stateStore
+	defer runner.stopBlockStream()
 	for {
+		// config is reloaded between checks
+		notificationService, config, vm := monitor.current(runner)
+		runner.blocks.setKeep(vm.RecentBlocksToCheck)
+		// the rpc in use is read before the checks start, since the validator checks can fail over to another endpoint
+		checkRPC := runner.currentRPC(vm)
+		runner.updateBlockStream(vm, checkRPC)
 		stats := ValidatorStats{}
 		var valErrs []IgnorableError
 		var sentryErrs []error
@@ -235,8 +396,10 @@ func runMonitor(
 				rpcRetries = rpcErrorRetries
 			}
 
+			// with multiple rpc endpoints, an out of sync endpoint is also failed over
+			failover := len(vm.rpcEndpoints()) > 1
 			for i := 0; i < rpcRetries; i++ {
-				valErrs = monitorValidator(config, vm, &stats)
+				valErrs = monitorValidator(config, vm, runner.currentRPC(vm), &stats, runner.blocks)
 				if len(valErrs) == 0 {
 					fmt.Printf("No errors found for validator: %s\n", vm.Name)
 					break
@@ -244,7 +407,7 @@ func runMonitor(
 				fmt.Printf("Got validator errors: +%v\n", valErrs)
 				foundNonRPCError := false
 				for _, err := range valErrs {
-					if _, ok := err.(*GenericRPCError); !ok {
+					if _, ok := err.(*GenericRPCError); !ok && !(failover && isRPCEndpointError(err)) {
 						foundNonRPCError = true
 						break
 					}
@@ -252,6 +415,10 @@ func runMonitor(
 				if foundNonRPCError {
 					break
 				}
+				if failover {
+					runner.rotateRPC(vm)
+					fmt.Printf("Failing over to rpc: %s\n", getRPCEndpointName(runner.currentRPC(vm).URL))
+				}
 				if i < rpcRetries-1 {
 					fmt.Println("Found only RPC errors, retrying")
 					time.Sleep(time.Duration((i*i)+1) * time.Second) // exponential backoff retry
@@ -261,6 +428,36 @@ func runMonitor(
 			wg.Done()
 		}()
 
+		if len(vm.rpcEndpoints()) > 1 {
+			wg.Add(1)
+			go func() {
+				stats.RPCEndpoints = monitorRPCEndpoints(vm)
+				wg.Done()
+			}()
+		}
+
+		consensusRPC := vm.consensusRPC(checkRPC)
+		wg.Add(1)
+		go func() {
+			consensus, err := getConsensusStats(consensusRPC)
+			if err != nil {
+				// rpc errors are alerted by the validator checks
+				fmt.Printf("Error getting consensus state for %s: %v\n", vm.Name, err)
+			}
+			stats.Consensus = consensus
+			wg.Done()
+		}()
+
+		var nodePeers []nodePeer
+		var nodeErr error
+		if vm.NodeRPC.URL != "" {
+			wg.Add(1)
+			go func() {
+				nodePeers, nodeErr = getNodePeers(vm.NodeRPC)
+				wg.Done()
+			}()
+		}
+
 		if vm.Sentries != nil {
 			wg.Add(1)
 			go func() {
@@ -276,35 +473,101 @@ func runMonitor(
 
 		wg.Wait()
 
+		if runner.stopped() {
+			// validator was removed from the config during this check
+			return
+		}
+
+		for _, endpointStats := range stats.RPCEndpoints {
+			endpointStats.Current = endpointStats.Name == getRPCEndpointName(runner.currentRPC(vm).URL)
+		}
+
 		errs := []error{}
 		if len(valErrs) > 0 {
 			for _, e := range valErrs {
+				recordRPCErrorMetrics(vm, e)
 				if e.Active(config.AlertConfig) {
 					errs = append(errs, e)
 				}
 			}
 		}
 		if len(sentryErrs) > 0 {
-			errs = append(errs, sentryErrs...)
+			for _, e := range sentryErrs {
+				recordRPCErrorMetrics(vm, e)
+				// sentry errors that cannot be ignored are always alerted
+				if active, ok := e.(AlertActive); ok && !active.Active(config.AlertConfig) {
+					continue
+				}
+				errs = append(errs, e)
+			}
+		}
+
+		errs = append(errs, activeErrors(config.AlertConfig, signerEvidence.errorsFor(vm))...)
+
+		if nodeErr != nil {
+			if err := newNodeRPCError(nodeErr.Error()); err.Active(config.AlertConfig) {
+				stats.increaseAlertLevel(alertLevelWarning)
+				errs = append(errs, err)
+			}
+		} else if vm.NodeRPC.URL != "" {
+			alertStateLock.Lock()
+			sentryNodeIDs := copySentryStrings(alertState.SentryNodeIDs)
+			alertStateLock.Unlock()
+			errs = append(errs, getTopologyErrors(vm, &stats, nodePeers, sentryNodeIDs, config.AlertConfig)...)
+		}
+
+		errs = append(errs, getForkErrors(vm, runner.currentRPC(vm), &stats, config.AlertConfig)...)
+
+		if stats.Consensus != nil {
+			alertStateLock.Lock()
+			alertState.trackConsensus(stats.Consensus)
+			alertStateLock.Unlock()
+			errs = append(errs, getConsensusErrors(vm, &stats, config.AlertConfig)...)
 		}
 
-		aggregatedErrs := stats.determineAggregatedErrorsAndAlertLevel(vm)
+		aggregatedErrs := stats.determineAggregatedErrorsAndAlertLevel(vm, config.AlertConfig)
 		if len(aggregatedErrs) > 0 {
 			errs = append(errs, aggregatedErrs...)
 		}
 
 		alertStateLock.Lock()
 		notification := getAlertNotification(config, vm, &stats, alertState, errs)
+		alertStateSnapshot := alertState.snapshot()
 		alertStateLock.Unlock()
 
+		stateStore.saveAlertState(vm.Name, alertStateSnapshot)
+		recordValidatorMetrics(vm, stats)
+
 		if notification != nil {
 			notificationService.SendValidatorAlertNotification(config, vm, stats, notification)
 		}
 
-		notificationService.UpdateValidatorRealtimeStatus(configFile, config, vm, stats, writeConfigMutex)
+		notificationService.UpdateValidatorRealtimeStatus(config, vm, stats, stateStore)
+
+		select {
+		case <-runner.stop:
+			return
+		case <-time.After(30 * time.Second):
+		}
+	}
+}
+
+// errors that are not ignored by the alert config
+func activeErrors(config AlertConfig, errs []IgnorableError) (active []error) {
+	for _, err := range errs {
+		if err.Active(config) {
+			active = append(active, err)
+		}
+	}
+	return
+}
 
-		time.Sleep(30 * time.Second)
+// sentry errors are a warning until they have been seen for the threshold number of checks
+func getSentryAlertLevel(count int64, threshold int64) AlertLevel {
+	if count >= threshold {
+		return alertLevelHigh
 	}
+	return alertLevelWarning
 }
 
 func (stats *ValidatorStats) increaseAlertLevel(alertLevel AlertLevel) {
@@ -314,10 +577,11 @@ func (stats *ValidatorStats) increaseAlertLevel(alertLevel AlertLevel) {
 }
 
 // determine alert level and any additional errors now that RPC And sentry checks are complete
-func (stats *ValidatorStats) determineAggregatedErrorsAndAlertLevel(vm *ValidatorMonitor) (errs []error) {
+func (stats *ValidatorStats) determineAggregatedErrorsAndAlertLevel(vm *ValidatorMonitor, alertConfig AlertConfig) (errs []error) {
 	sentryErrorCount := 0
 	for _, sentryStat := range stats.SentryStats {
-		if sentryStat.SentryAlertType != sentryAlertTypeGRPCError {
+		// a catching up sentry is expected to be behind, so it is only alerted as catching up
+		if sentryStat.SentryAlertType != sentryAlertTypeGRPCError && sentryStat.SentryAlertType != sentryAlertTypeCatchingUp {
 			if stats.Height-sentryStat.Height > outOfSyncThreshold {
 				errs = append(errs, newSentryOutOfSyncError(sentryStat.Name, fmt.Sprintf("Height: %d not in sync with RPC Height: %d", sentryStat.Height, stats.Height)))
 				sentryStat.SentryAlertType = sentryAlertTypeOutOfSyncError
@@ -335,14 +599,24 @@ func (stats *ValidatorStats) determineAggregatedErrorsAndAlertLevel(vm *Validato
 		}
 	}
 
+	versionErrs := activeErrors(alertConfig, getSentryVersionErrors(vm, stats))
+	if len(versionErrs) > 0 {
+		stats.increaseAlertLevel(alertLevelWarning)
+		errs = append(errs, versionErrs...)
+	}
+
 	// If all sentries have errors, set overall alert level to high
 	if sentryErrorCount > 0 && sentryErrorCount == len(stats.SentryStats) {
 		stats.increaseAlertLevel(alertLevelHigh)
 	}
 
-	if !vm.FullNode {
+	if stats.Inactive {
+		stats.increaseAlertLevel(alertLevelWarning)
+	}
+
+	if !stats.fullNode(vm) {
 		if stats.Height == stats.LastSignedBlockHeight {
-			if stats.RecentMissedBlocks == 0 {
+			if stats.RecentMissedBlocks == 0 && stats.RecentNilVotes == 0 {
 				if stats.SlashingPeriodUptime > vm.SlashingPeriodUptimeWarningThreshold {
 					// no recent missed blocks and above warning threshold for slashing period uptime, all good
 					return
@@ -351,7 +625,7 @@ func (stats *ValidatorStats) determineAggregatedErrorsAndAlertLevel(vm *Validato
 				stats.increaseAlertLevel(alertLevelWarning)
 				return
 			}
-			// Warning for missing recent blocks, but have signed current block
+			// Warning for missing or voting nil for recent blocks, but have signed current block
 			stats.increaseAlertLevel(alertLevelWarning)
 			return
 		}
@@ -386,6 +660,11 @@ func getAlertNotification(
 	var foundSentryGRPCErrors []string
 	var foundSentryOutOfSyncErrors []string
 	var foundSentryHaltErrors []string
+	var foundSentryCatchingUpErrors []string
+	var foundSentryLowPeersErrors []string
+	var foundSentrySyncInfoErrors []string
+	var foundGovVoteProposals []uint64
+	foundUpgradePlan := false
 	alertNotification := ValidatorAlertNotification{AlertLevel: alertLevelNone}
 
 	setAlertLevel := func(al AlertLevel) {
@@ -394,8 +673,22 @@ func getAlertNotification(
 		}
 	}
 
-	addAlert := func(err error) {
+	addAlert := func(key string, err error, alertLevel AlertLevel) {
 		alertNotification.Alerts = append(alertNotification.Alerts, err.Error())
+		alertNotification.AlertKeys = append(alertNotification.AlertKeys, key)
+		alertNotification.AlertLevels = append(alertNotification.AlertLevels, alertLevel)
+		setAlertLevel(alertLevel)
+		// informational notices are not cleared, so their level is not kept
+		if alertLevel > alertState.AlertKeyLevels[key] {
+			alertState.AlertKeyLevels[key] = alertLevel
+		}
+	}
+
+	addClearedAlert := func(key string, msg string) {
+		alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, msg)
+		alertNotification.ClearedAlertKeys = append(alertNotification.ClearedAlertKeys, key)
+		alertNotification.ClearedAlertLevels = append(alertNotification.ClearedAlertLevels, alertState.AlertKeyLevels[key])
+		delete(alertState.AlertKeyLevels, key)
 	}
 
 	shouldNotifyForFoundAlertType := func(alertType AlertType) bool {
@@ -407,8 +700,7 @@ func getAlertNotification(
 
 	handleGenericAlert := func(err error, alertType AlertType, alertLevel AlertLevel) {
 		if shouldNotifyForFoundAlertType(alertType) {
-			addAlert(err)
-			setAlertLevel(alertLevel)
+			addAlert(string(alertType), err, alertLevel)
 		}
 	}
 
@@ -421,10 +713,37 @@ func getAlertNotification(
 		sentryGRPCNotifyThreshold = sentryGRPCErrorNotifyThreshold
 	}
 
+	// the upgrade plan is tracked before the errors so halts at the upgrade height are known,
+	// reminders are sent when the plan is first seen and as the estimated upgrade time approaches
+	if plan := stats.UpgradePlan; stats.UpgradeChecked && plan != nil {
+		foundUpgradePlan = true
+		if alertState.UpgradePlanName != plan.Name || alertState.UpgradePlanHeight != plan.Height {
+			alertState.UpgradePlanName = plan.Name
+			alertState.UpgradePlanHeight = plan.Height
+			alertState.UpgradeReminder = 0
+		}
+		if err := newUpgradeReminderError(plan.Name, plan.Height, plan.EstimatedTime); err.Active(config.AlertConfig) {
+			if reminder := getUpgradeReminder(plan.EstimatedTime); reminder > alertState.UpgradeReminder {
+				alertState.UpgradeReminder = reminder
+				addAlert(upgradeAlertKey(plan.Name), err, getUpgradeReminderAlertLevel(reminder))
+			}
+		}
+	}
+
 	for _, err := range errs {
 		switch err := err.(type) {
 		case *JailedError:
 			handleGenericAlert(err, alertTypeJailed, alertLevelHigh)
+		case *DoubleSignError:
+			signerEvidence.report(vm, err.evidence)
+			if alertState.markEvidenceReported(err.evidence.Hash) {
+				recordDoubleSignMetrics(vm)
+				addAlert(doubleSignAlertKey(err.evidence.Hash), err, alertLevelCritical)
+			}
+		case *SignerDoubleSignError:
+			if alertState.markEvidenceReported(err.evidence.Hash) {
+				addAlert(doubleSignAlertKey(err.evidence.Hash), err, alertLevelCritical)
+			}
 		case *TombstonedError:
 			handleGenericAlert(err, alertTypeTombstoned, alertLevelCritical)
 		case *OutOfSyncError:
@@ -432,7 +751,14 @@ func getAlertNotification(
 			stats.RPCError = true
 		case *ChainHaltError:
 			fmt.Printf("found chain halt error\n")
-			handleGenericAlert(err, alertTypeHalt, alertLevelHigh)
+			if alertState.isUpgradeHeight(stats.Height) {
+				// expected halt for the upgrade, downgraded until blocks resume
+				if upgradeErr := newUpgradeHaltError(alertState.UpgradePlanName, alertState.UpgradePlanHeight, err.durationNano); upgradeErr.Active(config.AlertConfig) {
+					handleGenericAlert(upgradeErr, alertTypeUpgradeHalt, alertLevelWarning)
+				}
+			} else {
+				handleGenericAlert(err, alertTypeHalt, alertLevelHigh)
+			}
 			stats.RPCError = true
 		case *BlockFetchError:
 			handleGenericAlert(err, alertTypeBlockFetch, alertLevelWarning)
@@ -447,18 +773,16 @@ func getAlertNotification(
 
 			if alertState.AlertTypeCounts[alertTypeSlashingSLA] == 0 {
 				alertState.AlertTypeCounts[alertTypeSlashingSLA]++
-				addAlert(err)
-				setAlertLevel(alertLevelHigh)
+				addAlert(string(alertTypeSlashingSLA), err, alertLevelHigh)
 			}
 		case *MissedRecentBlocksError:
 			addRecentMissedBlocksAlertIfNecessary := func(alertLevel AlertLevel) {
 				if shouldNotifyForFoundAlertType(alertTypeMissedRecentBlocks) || stats.RecentMissedBlocks != recentMissedBlocksCounter {
-					addAlert(err)
-					setAlertLevel(alertLevel)
+					addAlert(string(alertTypeMissedRecentBlocks), err, alertLevel)
 				}
 			}
 			if stats.RecentMissedBlocks > recentMissedBlocksCounter {
-				if stats.RecentMissedBlocks > vm.RecentMissedBlocksNotifyThreshold {
+				if stats.RecentMissedBlocks > vm.RecentMissedBlocksNotifyThreshold && !err.onlyNetworkWide() {
 					stats.RecentMissedBlockAlertLevel = alertLevelHigh
 					addRecentMissedBlocksAlertIfNecessary(alertLevelHigh)
 				} else {
@@ -473,6 +797,23 @@ func getAlertNotification(
 			if stats.RecentMissedBlocks > alertState.RecentMissedBlocksCounterMax {
 				alertState.RecentMissedBlocksCounterMax = stats.RecentMissedBlocks
 			}
+		case *JailMarginError:
+			// notify once for each alert level as the jail margin shrinks
+			foundAlertTypes = append(foundAlertTypes, alertTypeJailMargin)
+			alertState.AlertTypeCounts[alertTypeJailMargin]++
+			if alertLevel := err.alertLevel(); alertLevel > alertState.JailMarginAlertLevel {
+				alertState.JailMarginAlertLevel = alertLevel
+				addAlert(string(alertTypeJailMargin), err, alertLevel)
+			}
+		case *GovVoteError:
+			// notify once for each alert level as the end of the voting period approaches
+			foundGovVoteProposals = append(foundGovVoteProposals, err.proposalID)
+			if alertLevel := err.alertLevel(); alertLevel > alertState.GovVoteAlertLevels[err.proposalID] {
+				alertState.GovVoteAlertLevels[err.proposalID] = alertLevel
+				addAlert(govVoteAlertKey(err.proposalID), err, alertLevel)
+			}
+		case *NilVotesError:
+			handleGenericAlert(err, alertTypeNilVotes, alertLevelWarning)
 		case *GenericRPCError:
 			handleGenericAlert(err, alertTypeGenericRPC, alertLevelWarning)
 			stats.RPCError = true
@@ -480,41 +821,78 @@ func getAlertNotification(
 			sentryName := err.sentry
 			foundSentryGRPCErrors = append(foundSentryGRPCErrors, sentryName)
 			if alertState.SentryGRPCErrorCounts[sentryName]%vm.NotifyEvery == 0 || alertState.SentryGRPCErrorCounts[sentryName] == sentryGRPCErrorNotifyThreshold {
-				addAlert(err)
-				if alertState.SentryGRPCErrorCounts[sentryName] >= sentryGRPCNotifyThreshold {
-					setAlertLevel(alertLevelHigh)
-				} else {
-					setAlertLevel(alertLevelWarning)
-				}
+				addAlert(sentryAlertKey(sentryName, sentryAlertTypeGRPCError), err, getSentryAlertLevel(alertState.SentryGRPCErrorCounts[sentryName], sentryGRPCNotifyThreshold))
 			}
 			alertState.SentryGRPCErrorCounts[sentryName]++
 		case *SentryOutOfSyncError:
 			sentryName := err.sentry
 			foundSentryOutOfSyncErrors = append(foundSentryOutOfSyncErrors, sentryName)
 			if alertState.SentryOutOfSyncErrorCounts[sentryName]%vm.NotifyEvery == 0 || alertState.SentryOutOfSyncErrorCounts[sentryName] == sentryOutOfSyncErrorNotifyThreshold {
-				addAlert(err)
-				if alertState.SentryOutOfSyncErrorCounts[sentryName] >= sentryOutOfSyncErrorNotifyThreshold {
-					setAlertLevel(alertLevelHigh)
-				} else {
-					setAlertLevel(alertLevelWarning)
-				}
+				addAlert(sentryAlertKey(sentryName, sentryAlertTypeOutOfSyncError), err, getSentryAlertLevel(alertState.SentryOutOfSyncErrorCounts[sentryName], sentryOutOfSyncErrorNotifyThreshold))
 			}
 			alertState.SentryOutOfSyncErrorCounts[sentryName]++
+		case *SentryCatchingUpError:
+			sentryName := err.sentry
+			foundSentryCatchingUpErrors = append(foundSentryCatchingUpErrors, sentryName)
+			if alertState.SentryCatchingUpErrorCounts[sentryName]%vm.NotifyEvery == 0 || alertState.SentryCatchingUpErrorCounts[sentryName] == sentryCatchingUpErrorNotifyThreshold {
+				addAlert(sentryAlertKey(sentryName, sentryAlertTypeCatchingUp), err, getSentryAlertLevel(alertState.SentryCatchingUpErrorCounts[sentryName], sentryCatchingUpErrorNotifyThreshold))
+			}
+			alertState.SentryCatchingUpErrorCounts[sentryName]++
+		case *SentryLowPeersError:
+			sentryName := err.sentry
+			foundSentryLowPeersErrors = append(foundSentryLowPeersErrors, sentryName)
+			if alertState.SentryLowPeersErrorCounts[sentryName]%vm.NotifyEvery == 0 {
+				addAlert(sentryAlertKey(sentryName, sentryAlertTypeLowPeers), err, alertLevelWarning)
+			}
+			alertState.SentryLowPeersErrorCounts[sentryName]++
+		case *SentrySyncInfoError:
+			sentryName := err.sentry
+			foundSentrySyncInfoErrors = append(foundSentrySyncInfoErrors, sentryName)
+			if alertState.SentrySyncInfoErrorCounts[sentryName]%vm.NotifyEvery == 0 {
+				addAlert(sentryAlertKey(sentryName, sentryAlertTypeSyncInfo), err, alertLevelWarning)
+			}
+			alertState.SentrySyncInfoErrorCounts[sentryName]++
+		case *MissedProposalsError:
+			handleGenericAlert(err, alertTypeMissedProposals, alertLevelWarning)
+		case *SignatureLatencyError:
+			handleGenericAlert(err, alertTypeSignatureLatency, alertLevelWarning)
+		case *LowParticipationError:
+			handleGenericAlert(err, alertTypeLowParticipation, alertLevelHigh)
+		case *ConsensusRoundError:
+			handleGenericAlert(err, alertTypeConsensusRound, alertLevelHigh)
+		case *ConsensusStuckError:
+			handleGenericAlert(err, alertTypeConsensusStuck, alertLevelHigh)
+		case *ForkError:
+			handleGenericAlert(err, alertTypeFork, alertLevelCritical)
+		case *NodeRPCError:
+			handleGenericAlert(err, alertTypeNodeRPC, alertLevelWarning)
+		case *SentryConnectionsError:
+			handleGenericAlert(err, alertTypeSentryConnections, err.alertLevel())
+		case *UnexpectedPeersError:
+			handleGenericAlert(err, alertTypeUnexpectedPeers, alertLevelHigh)
+		case *SentryVersionDriftError:
+			handleGenericAlert(err, alertTypeSentryVersionDrift, alertLevelWarning)
+		case *SentryMinVersionError:
+			handleGenericAlert(err, alertTypeSentryMinVersion, alertLevelHigh)
+		case *SentryVersionChangedError:
+			// informational, sent once for each change
+			addAlert(sentryVersionChangedAlertKey(err.sentry), err, alertLevelNone)
 		case *SentryHaltError:
 			sentryName := err.sentry
 			foundSentryHaltErrors = append(foundSentryHaltErrors, sentryName)
+			if alertState.isUpgradeHeight(err.height) {
+				// expected halt for the upgrade, still found so the halt is not reported as cleared
+				continue
+			}
 			if alertState.SentryHaltErrorCounts[sentryName]%vm.NotifyEvery == 0 || alertState.SentryHaltErrorCounts[sentryName] == sentryHaltErrorNotifyThreshold {
-				addAlert(err)
-				if alertState.SentryHaltErrorCounts[sentryName] >= sentryHaltErrorNotifyThreshold {
-					setAlertLevel(alertLevelHigh)
-				} else {
-					setAlertLevel(alertLevelWarning)
-				}
+				addAlert(sentryAlertKey(sentryName, sentryAlertTypeHalt), err, getSentryAlertLevel(alertState.SentryHaltErrorCounts[sentryName], sentryHaltErrorNotifyThreshold))
 			}
 			alertState.SentryHaltErrorCounts[sentryName]++
+		case *ignoreableError:
+			handleGenericAlert(err, alertTypeValidatorConfig, alertLevelWarning)
 		default:
-			addAlert(err)
-			setAlertLevel(alertLevelWarning)
+			// counted as a generic rpc error so the alert is deduplicated and cleared with the other alert types
+			handleGenericAlert(err, alertTypeGenericRPC, alertLevelWarning)
 		}
 	}
 
@@ -537,31 +915,73 @@ func getAlertNotification(
 		// reset alert type if we didn't see it this time and it's either an RPC error or there are no RPC errors
 		// should only clear jailed, tombstoned, and missed recent blocks errors if there also isn't a generic RPC error or RPC server out of sync error
 		if !hasAlertType(i) && alertState.AlertTypeCounts[i] > 0 {
-			alertState.AlertTypeCounts[i] = 0
 			if isRPCError(i) || !foundRPCError {
 				alertState.AlertTypeCounts[i] = 0
 				switch i {
 				case alertTypeOutOfSync:
-					alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, "rpc server out of sync")
+					addClearedAlert(string(alertTypeOutOfSync), "rpc server out of sync")
 				case alertTypeGenericRPC:
-					alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, "generic rpc error")
+					addClearedAlert(string(alertTypeGenericRPC), "generic rpc error")
 				case alertTypeJailed:
-					alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, "jailed")
+					addClearedAlert(string(alertTypeJailed), "jailed")
 					alertNotification.NotifyForClear = true
 				case alertTypeTombstoned:
-					alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, "tombstoned")
+					addClearedAlert(string(alertTypeTombstoned), "tombstoned")
 					alertNotification.NotifyForClear = true
 				case alertTypeBlockFetch:
-					alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, "rpc block fetch error")
+					addClearedAlert(string(alertTypeBlockFetch), "rpc block fetch error")
 				case alertTypeMissedRecentBlocks:
-					alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, "missed recent blocks")
+					addClearedAlert(string(alertTypeMissedRecentBlocks), "missed recent blocks")
 					if alertState.RecentMissedBlocksCounterMax > vm.RecentMissedBlocksNotifyThreshold {
 						alertNotification.NotifyForClear = true
 					}
 					alertState.RecentMissedBlocksCounter = 0
 					alertState.RecentMissedBlocksCounterMax = 0
+				case alertTypeJailMargin:
+					addClearedAlert(string(alertTypeJailMargin), "jail margin recovered")
+					if alertState.JailMarginAlertLevel > alertLevelWarning {
+						alertNotification.NotifyForClear = true
+					}
+					alertState.JailMarginAlertLevel = alertLevelNone
+				case alertTypeMissedProposals:
+					addClearedAlert(string(alertTypeMissedProposals), "missed proposals")
+				case alertTypeSignatureLatency:
+					addClearedAlert(string(alertTypeSignatureLatency), "signing latency")
+				case alertTypeLowParticipation:
+					addClearedAlert(string(alertTypeLowParticipation), "low network participation")
+					alertNotification.NotifyForClear = true
+				case alertTypeConsensusRound:
+					addClearedAlert(string(alertTypeConsensusRound), "consensus round")
+				case alertTypeConsensusStuck:
+					addClearedAlert(string(alertTypeConsensusStuck), "consensus stuck")
+					alertNotification.NotifyForClear = true
+				case alertTypeFork:
+					addClearedAlert(string(alertTypeFork), "block hash divergence")
+					alertNotification.NotifyForClear = true
+				case alertTypeNodeRPC:
+					addClearedAlert(string(alertTypeNodeRPC), "validator node rpc error")
+				case alertTypeSentryConnections:
+					addClearedAlert(string(alertTypeSentryConnections), "validator sentry connections restored")
+					alertNotification.NotifyForClear = true
+				case alertTypeUnexpectedPeers:
+					addClearedAlert(string(alertTypeUnexpectedPeers), "unexpected validator peers")
+					alertNotification.NotifyForClear = true
+				case alertTypeSentryVersionDrift:
+					addClearedAlert(string(alertTypeSentryVersionDrift), "sentry version drift")
+				case alertTypeSentryMinVersion:
+					addClearedAlert(string(alertTypeSentryMinVersion), "sentries below minimum version")
+					alertNotification.NotifyForClear = true
+				case alertTypeUpgradeHalt:
+					addClearedAlert(string(alertTypeUpgradeHalt), "upgrade halt")
+				case alertTypeNilVotes:
+					addClearedAlert(string(alertTypeNilVotes), "nil votes")
+				case alertTypeHalt:
+					addClearedAlert(string(alertTypeHalt), "chain halt")
+					alertNotification.NotifyForClear = true
+				case alertTypeValidatorConfig:
+					addClearedAlert(string(alertTypeValidatorConfig), "validator configuration error")
 				case alertTypeSlashingSLA:
-					alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, "slashing sla uptime recovered")
+					addClearedAlert(string(alertTypeSlashingSLA), "slashing sla uptime recovered")
 					alertNotification.NotifyForClear = true
 				default:
 				}
@@ -581,7 +1001,7 @@ func getAlertNotification(
 				alertNotification.NotifyForClear = true
 			}
 			alertState.SentryGRPCErrorCounts[sentryName] = 0
-			alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, fmt.Sprintf("%s grpc error", sentryName))
+			addClearedAlert(sentryAlertKey(sentryName, sentryAlertTypeGRPCError), fmt.Sprintf("%s connection error", sentryName))
 		}
 	}
 	for sentryName := range alertState.SentryHaltErrorCounts {
@@ -605,7 +1025,7 @@ func getAlertNotification(
 				alertNotification.NotifyForClear = true
 			}
 			alertState.SentryHaltErrorCounts[sentryName] = 0
-			alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, fmt.Sprintf("%s halt error", sentryName))
+			addClearedAlert(sentryAlertKey(sentryName, sentryAlertTypeHalt), fmt.Sprintf("%s halt error", sentryName))
 		}
 	}
 	for sentryName := range alertState.SentryOutOfSyncErrorCounts {
@@ -629,7 +1049,86 @@ func getAlertNotification(
 				alertNotification.NotifyForClear = true
 			}
 			alertState.SentryOutOfSyncErrorCounts[sentryName] = 0
-			alertNotification.ClearedAlerts = append(alertNotification.ClearedAlerts, fmt.Sprintf("%s out of sync error", sentryName))
+			addClearedAlert(sentryAlertKey(sentryName, sentryAlertTypeOutOfSyncError), fmt.Sprintf("%s out of sync error", sentryName))
+		}
+	}
+	for sentryName := range alertState.SentryCatchingUpErrorCounts {
+		if !containsString(foundSentryCatchingUpErrors, sentryName) && !containsString(foundSentryGRPCErrors, sentryName) && alertState.SentryCatchingUpErrorCounts[sentryName] > 0 {
+			if alertState.SentryCatchingUpErrorCounts[sentryName] > sentryCatchingUpErrorNotifyThreshold {
+				alertNotification.NotifyForClear = true
+			}
+			alertState.SentryCatchingUpErrorCounts[sentryName] = 0
+			addClearedAlert(sentryAlertKey(sentryName, sentryAlertTypeCatchingUp), fmt.Sprintf("%s catching up", sentryName))
+		}
+	}
+	for sentryName := range alertState.SentryLowPeersErrorCounts {
+		if !containsString(foundSentryLowPeersErrors, sentryName) && !containsString(foundSentryGRPCErrors, sentryName) && alertState.SentryLowPeersErrorCounts[sentryName] > 0 {
+			alertState.SentryLowPeersErrorCounts[sentryName] = 0
+			addClearedAlert(sentryAlertKey(sentryName, sentryAlertTypeLowPeers), fmt.Sprintf("%s low peer count", sentryName))
+		}
+	}
+	for sentryName := range alertState.SentrySyncInfoErrorCounts {
+		if !containsString(foundSentrySyncInfoErrors, sentryName) && !containsString(foundSentryGRPCErrors, sentryName) && alertState.SentrySyncInfoErrorCounts[sentryName] > 0 {
+			alertState.SentrySyncInfoErrorCounts[sentryName] = 0
+			addClearedAlert(sentryAlertKey(sentryName, sentryAlertTypeSyncInfo), fmt.Sprintf("%s catching up and peers check", sentryName))
+		}
+	}
+
+	// the upgrade plan is removed from the chain when the upgrade is applied or cancelled
+	if stats.UpgradeChecked && !foundUpgradePlan && alertState.UpgradePlanName != "" {
+		// no reminders are sent for ignored upgrade alerts, so there is nothing to clear
+		if alertState.UpgradeReminder > 0 {
+			if stats.Height >= alertState.UpgradePlanHeight {
+				addClearedAlert(upgradeAlertKey(alertState.UpgradePlanName), fmt.Sprintf("upgrade %s completed, blocks resumed at height %d", alertState.UpgradePlanName, alertState.UpgradePlanHeight))
+			} else {
+				addClearedAlert(upgradeAlertKey(alertState.UpgradePlanName), fmt.Sprintf("upgrade %s cancelled", alertState.UpgradePlanName))
+			}
+			alertNotification.NotifyForClear = true
+		}
+		alertState.UpgradePlanName = ""
+		alertState.UpgradePlanHeight = 0
+		alertState.UpgradeReminder = 0
+	}
+
+	// leaving the active set is alerted once, and cleared once when the validator is back in the active set
+	if !vm.FullNode && stats.ActiveSetChecked {
+		if stats.Inactive && !alertState.Inactive {
+			if err := newInactiveError(); err.Active(config.AlertConfig) {
+				addAlert(string(alertTypeInactive), err, alertLevelWarning)
+				alertState.Inactive = true
+			}
+		} else if !stats.Inactive && alertState.Inactive {
+			addClearedAlert(string(alertTypeInactive), "not in active set, validator is now in the active set")
+			alertNotification.NotifyForClear = true
+			alertState.Inactive = false
+		}
+	}
+
+	// gov vote alerts are cleared when the validator votes or the voting period ends, which can only be known when proposals were checked
+	if vm.OperatorAddress == "" {
+		for proposalID := range alertState.GovVoteAlertLevels {
+			delete(alertState.AlertKeyLevels, govVoteAlertKey(proposalID))
+		}
+		alertState.GovVoteAlertLevels = make(map[uint64]AlertLevel)
+	} else if stats.GovChecked {
+		for proposalID := range alertState.GovVoteAlertLevels {
+			proposalFound := false
+			for _, foundProposalID := range foundGovVoteProposals {
+				if foundProposalID == proposalID {
+					proposalFound = true
+					break
+				}
+			}
+			if proposalFound {
+				continue
+			}
+			delete(alertState.GovVoteAlertLevels, proposalID)
+			alertNotification.NotifyForClear = true
+			if stats.hasVotedOnProposal(proposalID) {
+				addClearedAlert(govVoteAlertKey(proposalID), fmt.Sprintf("voted on proposal #%d", proposalID))
+			} else {
+				addClearedAlert(govVoteAlertKey(proposalID), fmt.Sprintf("voting period for proposal #%d ended without a vote", proposalID))
+			}
 		}
 	}
 
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// compares application versions such as v7.0.2, 7.0.2 or v7.0.2-rc1, returns -1, 0 or 1.
+// Versions with a pre-release suffix are lower than the same version without one.
+func compareVersions(a string, b string) int {
+	aRelease, aPreRelease := splitVersion(a)
+	bRelease, bPreRelease := splitVersion(b)
+	for i := 0; i < len(aRelease) || i < len(bRelease); i++ {
+		var aPart, bPart string
+		if i < len(aRelease) {
+			aPart = aRelease[i]
+		}
+		if i < len(bRelease) {
+			bPart = bRelease[i]
+		}
+		if c := compareVersionParts(aPart, bPart); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case aPreRelease == bPreRelease:
+		return 0
+	case aPreRelease == "":
+		return 1
+	case bPreRelease == "":
+		return -1
+	default:
+		return strings.Compare(aPreRelease, bPreRelease)
+	}
+}
+
+func splitVersion(version string) (release []string, preRelease string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.Index(version, "+"); i != -1 {
+		version = version[:i]
+	}
+	if i := strings.Index(version, "-"); i != -1 {
+		preRelease = version[i+1:]
+		version = version[:i]
+	}
+	return strings.Split(version, "."), preRelease
+}
+
+// numeric parts are compared as numbers, missing parts are treated as 0
+func compareVersionParts(a string, b string) int {
+	if a == "" {
+		a = "0"
+	}
+	if b == "" {
+		b = "0"
+	}
+	aNum, aErr := strconv.ParseInt(a, 10, 64)
+	bNum, bErr := strconv.ParseInt(b, 10, 64)
+	if aErr != nil || bErr != nil {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case aNum < bNum:
+		return -1
+	case aNum > bNum:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// version drift between sentries and sentries below the minimum version, for sentries with a known version
+func getSentryVersionErrors(vm *ValidatorMonitor, stats *ValidatorStats) (errs []IgnorableError) {
+	sentriesByVersion := make(map[string][]string)
+	var belowMinVersion []string
+	for _, sentryStats := range stats.SentryStats {
+		if sentryStats.Version == "" {
+			continue
+		}
+		sentriesByVersion[sentryStats.Version] = append(sentriesByVersion[sentryStats.Version], sentryStats.Name)
+		if vm.SentryMinVersion != "" && compareVersions(sentryStats.Version, vm.SentryMinVersion) < 0 {
+			belowMinVersion = append(belowMinVersion, fmt.Sprintf("%s (%s)", sentryStats.Name, sentryStats.Version))
+		}
+	}
+	if len(sentriesByVersion) > 1 {
+		var versions []string
+		for version, sentries := range sentriesByVersion {
+			sort.Strings(sentries)
+			versions = append(versions, fmt.Sprintf("%s: %s", version, strings.Join(sentries, ", ")))
+		}
+		sort.Strings(versions)
+		errs = append(errs, newSentryVersionDriftError(versions))
+	}
+	if len(belowMinVersion) > 0 {
+		sort.Strings(belowMinVersion)
+		errs = append(errs, newSentryMinVersionError(belowMinVersion, vm.SentryMinVersion))
+	}
+	return
+}